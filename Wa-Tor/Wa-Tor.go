@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+
+	"Wa-Tor/internal/cli"
+	"Wa-Tor/wator"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// snapshotKey saves a snapshot of the running simulation to
+// snapshot_<chronon>.json when pressed.
+const snapshotKey = ebiten.KeyS
+
+var (
+	rectImg *ebiten.Image // Shared rectangle image used for drawing cells.
+
+	fishColor  = color.RGBA{255, 255, 0, 255} // Color representing fish (yellow).
+	sharkColor = color.RGBA{255, 0, 0, 255}   // Color representing sharks (red).
+	waterColor = color.RGBA{0, 41, 58, 255}   // Color representing water (blue).
+	landColor  = color.RGBA{94, 76, 50, 255}  // Color representing land (brown).
+	reefColor  = color.RGBA{0, 110, 100, 255} // Color representing reefs (teal).
+
+	// palette maps each kind of cell to the colour it is drawn in.
+	palette = [...]color.Color{
+		wator.Water: waterColor,
+		wator.Fish:  fishColor,
+		wator.Shark: sharkColor,
+	}
+
+	// terrainPalette maps each kind of terrain to the colour of its empty cells.
+	terrainPalette = [...]color.Color{
+		wator.Sea:  waterColor,
+		wator.Land: landColor,
+		wator.Reef: reefColor,
+	}
+)
+
+// Game implements the Ebiten Game interface for the Wa-Tor simulation.
+type Game struct {
+	world      *wator.World
+	frameCount int
+	tpsSum     float64
+	log        *cli.CSVLog
+	windowX    int // Window width in pixels.
+	windowY    int // Window height in pixels.
+	cellXSize  int // Width of each cell in pixels.
+	cellYSize  int // Height of each cell in pixels.
+	rowShift   int // Horizontal offset of odd rows in pixels, for hexagonal grids.
+
+	debug bool // Check the world's invariants after every chronon.
+}
+
+// Update updates the state of the simulation and logs data to CSV.
+func (g *Game) Update() error {
+	g.frameCount++
+	currentTPS := ebiten.ActualTPS()
+	g.tpsSum += currentTPS
+
+	if inpututil.IsKeyJustPressed(snapshotKey) {
+		cli.SaveSnapshot(g.world, fmt.Sprintf("snapshot_%d.json", g.world.Chronon()))
+	}
+
+	g.world.Step()
+	if g.debug {
+		if err := g.world.Check(); err != nil {
+			return err // Stop at the first broken invariant.
+		}
+	}
+
+	if g.log != nil {
+		if err := g.log.Write(g.frameCount, currentTPS, g.world); err != nil {
+			fmt.Println("Error writing to CSV:", err)
+		}
+	}
+
+	return nil
+}
+
+// Draw draws the simulation grid to the screen.
+//
+// On a hexagonal grid every odd row is shifted half a cell to the east, so
+// the cells are laid out like bricks and each one touches exactly the six
+// cells it counts as neighbours.
+func (g *Game) Draw(screen *ebiten.Image) {
+	for i := 0; i < g.world.Width(); i++ {
+		for k := 0; k < g.world.Height(); k++ {
+			cell := g.world.Cell(i, k)
+			c := palette[cell.Kind]
+			if cell.Kind == wator.Water {
+				c = terrainPalette[g.world.Terrain(i, k)]
+			}
+			drawRectangle(screen, i*g.cellXSize+(k%2)*g.rowShift, k*g.cellYSize, c)
+		}
+	}
+	// Draw a background rectangle for the TPS display
+	tpsBackground := ebiten.NewImage(120, 30)    // Width: 120px, Height: 30px
+	tpsBackground.Fill(color.RGBA{0, 0, 0, 180}) // Semi-transparent black background
+
+	// Draw the background rectangle at a fixed position
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(10, 10) // Position at (10, 10)
+	screen.DrawImage(tpsBackground, op)
+
+	// Draw the TPS text over the background
+	msg := fmt.Sprintf("TPS: %.2f", ebiten.ActualTPS())
+	ebitenutil.DebugPrintAt(screen, msg, 20, 20) // Position text within the background
+}
+
+// Layout defines the layout of the game window.
+func (g *Game) Layout(_, _ int) (int, int) {
+	return g.windowX, g.windowY
+}
+
+// drawRectangle draws a single cell-sized rectangle to the screen with its
+// top-left corner at (x, y).
+func drawRectangle(screen *ebiten.Image, x, y int, c color.Color) {
+	rectImg.Fill(c)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(rectImg, op)
+}
+
+// checkWindow reports whether the window in s is too small to draw its grid.
+func checkWindow(s cli.Settings) error {
+	width := s.Width
+	if s.Neighbourhood == wator.Hex {
+		width++ // Odd rows are shifted half a cell to the east.
+	}
+	if s.WindowWidth < width || s.WindowHeight < s.Height {
+		return fmt.Errorf("a %dx%d window is too small to draw a %dx%d grid, which needs at least %dx%d pixels",
+			s.WindowWidth, s.WindowHeight, s.Width, s.Height, width, s.Height)
+	}
+	return nil
+}
+
+// main initializes the simulation and runs the game loop. Runs without a
+// window are handled by cmd/wator-headless, which does not need a display.
+func main() {
+	s := cli.DefaultSettings()
+	cli.RegisterFlags(flag.CommandLine, &s)
+	cli.RegisterWindowFlags(flag.CommandLine, &s)
+	configPath := flag.String("config", "", "JSON, YAML or TOML file of parameters; flags override it")
+	csvPath := flag.String("csv", "", "file to log TPS and population data to (default tps_data_<threads>.csv)")
+	exportPath := flag.String("export-map", "", "write the final world to this PNG or text map file when the window is closed")
+	resumePath := flag.String("resume", "", "continue the run saved in this snapshot; its parameters replace the simulation flags")
+	savePath := flag.String("save", "", "write a snapshot to this file when the window is closed")
+	debug := flag.Bool("debug", false, "check the world for inconsistencies after every chronon and stop at the first")
+	flag.Parse()
+
+	if *configPath != "" {
+		if err := cli.ApplyConfigFile(flag.CommandLine, *configPath, &s); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	world, err := cli.OpenWorld(&s, *resumePath)
+	if err == nil {
+		err = checkWindow(s) // Checked once the grid size of a resumed run is known.
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer world.Close()
+	if *csvPath == "" {
+		*csvPath = fmt.Sprintf("tps_data_%d.csv", s.Threads)
+	}
+
+	game := &Game{
+		world:     world,
+		windowX:   s.WindowWidth,
+		windowY:   s.WindowHeight,
+		cellXSize: s.WindowWidth / s.Width,
+		cellYSize: s.WindowHeight / s.Height,
+		debug:     *debug,
+	}
+	if s.Neighbourhood == wator.Hex {
+		game.cellXSize = 2 * s.WindowWidth / (2*s.Width + 1) // Leave room for the shifted rows.
+		game.rowShift = game.cellXSize / 2
+	}
+	rectImg = ebiten.NewImage(game.cellXSize, game.cellYSize) // Initialize shared rectangle image.
+
+	// Create the CSV file, logging busy, wait and load columns per thread
+	log, err := cli.CreateCSVLog(*csvPath, s.Threads)
+	if err != nil {
+		panic(err)
+	}
+	defer log.Close()
+	game.log = log
+
+	ebiten.SetWindowSize(s.WindowWidth, s.WindowHeight)
+	ebiten.SetWindowTitle("Go Wa-Tor World")
+
+	// Run the game loop
+	if err := ebiten.RunGame(game); err != nil {
+		var broken *wator.InvariantError
+		if !errors.As(err, &broken) {
+			panic(err)
+		}
+		fmt.Fprintln(os.Stderr, "Invariant broken:", err) // Still export and save the broken world below.
+	}
+	cli.ExportMap(world, *exportPath)
+	cli.SaveSnapshot(world, *savePath)
+}
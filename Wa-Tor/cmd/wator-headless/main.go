@@ -0,0 +1,71 @@
+// Command wator-headless runs the Wa-Tor simulation without a window and
+// reports how fast it went, or benchmarks it with the bench subcommand. It
+// does not depend on Ebiten, so it builds and runs on machines without a
+// display or X11 libraries, such as CI boxes and servers.
+//
+// It accepts the same simulation flags and config files as the windowed
+// program; window settings in a config file are ignored.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"Wa-Tor/internal/cli"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		if err := cli.RunBench(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	s := cli.DefaultSettings()
+	cli.RegisterFlags(flag.CommandLine, &s)
+	configPath := flag.String("config", "", "JSON, YAML or TOML file of parameters; flags override it")
+	csvPath := flag.String("csv", "", "file to log TPS and population data to for every chronon (default none)")
+	chronons := flag.Int("chronons", 1000, "number of chronons to run")
+	exportPath := flag.String("export-map", "", "write the final world to this PNG or text map file when the run ends")
+	resumePath := flag.String("resume", "", "continue the run saved in this snapshot; its parameters replace the simulation flags")
+	savePath := flag.String("save", "", "write a snapshot to this file when the run ends")
+	debug := flag.Bool("debug", false, "check the world for inconsistencies after every chronon and stop at the first")
+	flag.Parse()
+
+	if *configPath != "" {
+		if err := cli.ApplyConfigFile(flag.CommandLine, *configPath, &s); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	world, err := cli.OpenWorld(&s, *resumePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer world.Close()
+
+	var log *cli.CSVLog
+	if *csvPath != "" {
+		if log, err = cli.CreateCSVLog(*csvPath, world.Config().Threads); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		defer log.Close()
+	}
+
+	err = cli.RunHeadless(world, *chronons, *debug, log)
+	cli.ExportMap(world, *exportPath)
+	cli.SaveSnapshot(world, *savePath) // Keep a broken world for inspection, too.
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invariant broken:", err)
+		if log != nil {
+			log.Close() // Deferred calls do not run on exit.
+		}
+		world.Close()
+		os.Exit(1)
+	}
+}
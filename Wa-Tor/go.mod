@@ -2,7 +2,11 @@ module Wa-Tor
 
 go 1.23.1
 
-require github.com/hajimehoshi/ebiten/v2 v2.8.5
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/hajimehoshi/ebiten/v2 v2.8.5
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/ebitengine/gomobile v0.0.0-20241016134836-cc2e38a7c0ee // indirect
@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"Wa-Tor/wator"
+)
+
+// benchResult summarises the step times of one grid size and thread count.
+type benchResult struct {
+	width, height int
+	threads       int
+	runs          int           // Number of runs, each giving one sample.
+	mean          time.Duration // Mean of the runs' average step times.
+	median        time.Duration
+	stddev        time.Duration
+	speedup       float64 // Mean step time with one thread divided by mean.
+	efficiency    float64 // Speedup per thread.
+	imbalance     float64 // Mean over the runs of the busiest worker's load relative to the average.
+}
+
+// RunBench implements the bench subcommand: it runs the simulation headless
+// for every combination of grid size, thread count and seed, repeating each,
+// and writes a summary of the step times to a single CSV file. args are the
+// arguments following the subcommand.
+func RunBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	configPath := fs.String("config", "", "JSON, YAML or TOML file of base parameters")
+	threadList := fs.String("threads", "1,2,4,8", "comma-separated thread counts to compare")
+	sizeList := fs.String("sizes", "150x150", "comma-separated grid sizes, as WIDTHxHEIGHT")
+	seedList := fs.String("seeds", "1,2,3", "comma-separated seeds to run each configuration with")
+	repeats := fs.Int("repeats", 3, "number of times to repeat each seed")
+	chronons := fs.Int("chronons", 200, "number of chronons timed per run")
+	warmup := fs.Int("warmup", 10, "number of chronons run before timing starts")
+	fs.String("mode", "", "update strategy (default from the base parameters)")
+	fs.Bool("pool", false, "run the workers on a persistent goroutine pool instead of starting goroutines every pass (default from the base parameters)")
+	fs.String("schedule", "", "how the grid is shared between threads: static or dynamic (default from the base parameters)")
+	outPath := fs.String("out", "bench_results.csv", "file to write the results to")
+	if err := fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	base, err := benchBase(fs, *configPath)
+	if err != nil {
+		return err
+	}
+	threads, err := parseInts(*threadList)
+	if err != nil {
+		return fmt.Errorf("-threads: %w", err)
+	}
+	if !slices.Contains(threads, 1) {
+		threads = append([]int{1}, threads...) // Speedup is relative to one thread.
+	}
+	seeds, err := parseInts(*seedList)
+	if err != nil {
+		return fmt.Errorf("-seeds: %w", err)
+	}
+	sizes, err := parseSizes(*sizeList)
+	if err != nil {
+		return fmt.Errorf("-sizes: %w", err)
+	}
+	if *repeats < 1 || *chronons < 1 || *warmup < 0 {
+		return fmt.Errorf("repeats and chronons must be at least 1 and warmup at least 0")
+	}
+
+	var results []benchResult
+	for _, size := range sizes {
+		cfg := scaledConfig(base, size[0], size[1])
+		var baseline time.Duration
+		for _, t := range threads {
+			cfg.Threads = t
+			var samples []time.Duration
+			var imbalance float64
+			for _, seed := range seeds {
+				cfg.Seed = uint64(seed)
+				for range *repeats {
+					step, load, err := benchRun(cfg, *warmup, *chronons)
+					if err != nil {
+						return err
+					}
+					samples = append(samples, step)
+					imbalance += loadImbalance(load)
+				}
+			}
+			r := summarise(samples)
+			r.width, r.height, r.threads = size[0], size[1], t
+			r.imbalance = imbalance / float64(r.runs)
+			if t == 1 {
+				baseline = r.mean
+			}
+			results = append(results, r)
+		}
+		for i := range results {
+			if r := &results[i]; r.width == size[0] && r.height == size[1] {
+				r.speedup = float64(baseline) / float64(r.mean)
+				r.efficiency = r.speedup / float64(r.threads)
+			}
+		}
+	}
+
+	if err := writeBenchResults(*outPath, base, *chronons, results); err != nil {
+		return err
+	}
+	printBenchResults(results)
+	fmt.Printf("Results written to %s\n", *outPath)
+	return nil
+}
+
+// benchBase returns the parameters every run of a sweep starts from: the
+// defaults, or the config file at configPath, with the -mode, -schedule and
+// -pool flags applied on top if they were set on fs. Maps fix the size of
+// the grid, so a base with a terrain or world map is an error.
+func benchBase(fs *flag.FlagSet, configPath string) (wator.Config, error) {
+	base := DefaultSettings()
+	if configPath != "" {
+		if err := loadSettings(configPath, &base); err != nil {
+			return wator.Config{}, err
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "mode":
+			base.Mode = wator.Mode(f.Value.String())
+		case "schedule":
+			base.Schedule = wator.Schedule(f.Value.String())
+		case "pool":
+			base.Pool = f.Value.String() == "true"
+		}
+	})
+	if base.TerrainMap != "" || base.WorldMap != "" {
+		return wator.Config{}, errors.New("bench resizes the grid to each of -sizes, so the base parameters cannot use a terrain or world map")
+	}
+	return base.Config, nil
+}
+
+// scaledConfig returns cfg resized to width x height, with the starting
+// populations scaled to keep the same density of fish and sharks.
+func scaledConfig(cfg wator.Config, width, height int) wator.Config {
+	area := float64(width*height) / float64(cfg.Width*cfg.Height)
+	cfg.NumFish = int(math.Round(float64(cfg.NumFish) * area))
+	cfg.NumShark = int(math.Round(float64(cfg.NumShark) * area))
+	cfg.Width, cfg.Height = width, height
+	return cfg
+}
+
+// benchRun runs one world for warmup chronons and then times n more,
+// returning the average time per chronon and the number of creatures each
+// worker updated in the timed chronons.
+func benchRun(cfg wator.Config, warmup, n int) (time.Duration, []int, error) {
+	world, err := wator.New(cfg)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer world.Close()
+	for range warmup {
+		world.Step()
+	}
+	var total time.Duration
+	load := make([]int, cfg.Threads)
+	for range n {
+		world.Step()
+		tm := world.Timing()
+		total += tm.Step
+		for t, c := range tm.Creatures {
+			load[t] += c
+		}
+	}
+	return total / time.Duration(n), load, nil
+}
+
+// summarise returns the mean, median and sample standard deviation of the
+// given step times.
+func summarise(samples []time.Duration) benchResult {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	n := len(sorted)
+
+	var sum float64
+	for _, s := range sorted {
+		sum += float64(s)
+	}
+	mean := sum / float64(n)
+	var squares float64
+	for _, s := range sorted {
+		squares += (float64(s) - mean) * (float64(s) - mean)
+	}
+	stddev := 0.0
+	if n > 1 {
+		stddev = math.Sqrt(squares / float64(n-1))
+	}
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	return benchResult{
+		runs:   n,
+		mean:   time.Duration(mean),
+		median: median,
+		stddev: time.Duration(stddev),
+	}
+}
+
+// writeBenchResults writes one row per grid size and thread count to the
+// CSV file at path, labelled with the mode, schedule and workers of base.
+func writeBenchResults(path string, base wator.Config, chronons int, results []benchResult) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"Mode", "Schedule", "Pool", "Width", "Height", "Threads", "Runs", "Chronons",
+		"MeanStepNs", "MedianStepNs", "StddevStepNs", "Speedup", "Efficiency", "LoadImbalance"})
+	for _, r := range results {
+		w.Write([]string{
+			string(base.Mode),
+			string(base.Schedule),
+			strconv.FormatBool(base.Pool),
+			strconv.Itoa(r.width),
+			strconv.Itoa(r.height),
+			strconv.Itoa(r.threads),
+			strconv.Itoa(r.runs),
+			strconv.Itoa(chronons),
+			strconv.FormatInt(r.mean.Nanoseconds(), 10),
+			strconv.FormatInt(r.median.Nanoseconds(), 10),
+			strconv.FormatInt(r.stddev.Nanoseconds(), 10),
+			fmt.Sprintf("%.3f", r.speedup),
+			fmt.Sprintf("%.3f", r.efficiency),
+			fmt.Sprintf("%.3f", r.imbalance),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// printBenchResults prints the results as an aligned table.
+func printBenchResults(results []benchResult) {
+	fmt.Printf("%-9s %7s %12s %12s %12s %8s %10s %9s\n",
+		"Size", "Threads", "Mean", "Median", "Stddev", "Speedup", "Efficiency", "Imbalance")
+	for _, r := range results {
+		fmt.Printf("%-9s %7d %12v %12v %12v %8.2f %9.0f%% %9.2f\n",
+			fmt.Sprintf("%dx%d", r.width, r.height), r.threads,
+			r.mean, r.median, r.stddev, r.speedup, 100*r.efficiency, r.imbalance)
+	}
+}
+
+// parseInts parses a comma-separated list of positive integers.
+func parseInts(list string) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(list, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || v < 1 {
+			return nil, fmt.Errorf("%q is not a positive integer", field)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// parseSizes parses a comma-separated list of grid sizes such as "100x80".
+func parseSizes(list string) ([][2]int, error) {
+	var sizes [][2]int
+	for _, field := range strings.Split(list, ",") {
+		w, h, ok := strings.Cut(strings.TrimSpace(field), "x")
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if !ok || errW != nil || errH != nil || width < 1 || height < 1 {
+			return nil, fmt.Errorf("%q is not a size such as 150x150", field)
+		}
+		sizes = append(sizes, [2]int{width, height})
+	}
+	return sizes, nil
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"Wa-Tor/wator"
+)
+
+func TestSummarise(t *testing.T) {
+	tests := []struct {
+		name                 string
+		samples              []time.Duration
+		mean, median, stddev time.Duration
+	}{
+		{"one sample", []time.Duration{7}, 7, 7, 0},
+		{"odd count", []time.Duration{9, 1, 5}, 5, 5, 4},
+		{"even count", []time.Duration{4, 1, 3, 2}, 2, 2, 1},
+		{"equal samples", []time.Duration{6, 6, 6, 6}, 6, 6, 0},
+		{"skewed", []time.Duration{10, 10, 10, 50}, 20, 10, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := slices.Clone(tt.samples)
+			r := summarise(samples)
+			if r.runs != len(tt.samples) || r.mean != tt.mean || r.median != tt.median || r.stddev != tt.stddev {
+				t.Errorf("got %d runs, mean %d, median %d, stddev %d; want %d, %d, %d, %d",
+					r.runs, r.mean, r.median, r.stddev, len(tt.samples), tt.mean, tt.median, tt.stddev)
+			}
+			if !slices.Equal(samples, tt.samples) {
+				t.Errorf("summarise reordered its samples to %v", samples)
+			}
+		})
+	}
+}
+
+func TestParseSizes(t *testing.T) {
+	tests := []struct {
+		list string
+		want [][2]int // nil if the list is invalid.
+	}{
+		{"150x150", [][2]int{{150, 150}}},
+		{"150x150,300x200", [][2]int{{150, 150}, {300, 200}}},
+		{" 40x30 , 8x6", [][2]int{{40, 30}, {8, 6}}},
+		{"1x1", [][2]int{{1, 1}}},
+		{"", nil},
+		{"150", nil},
+		{"150x", nil},
+		{"x150", nil},
+		{"0x150", nil},
+		{"150x-1", nil},
+		{"150X150", nil},
+		{"150x150,", nil},
+		{"axb", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			got, err := parseSizes(tt.list)
+			switch {
+			case tt.want == nil && err == nil:
+				t.Errorf("accepted %q as %v", tt.list, got)
+			case tt.want != nil && err != nil:
+				t.Error(err)
+			case !slices.Equal(got, tt.want):
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInts(t *testing.T) {
+	tests := []struct {
+		list string
+		want []int // nil if the list is invalid.
+	}{
+		{"1", []int{1}},
+		{"1,2,4,8", []int{1, 2, 4, 8}},
+		{" 3 , 5", []int{3, 5}},
+		{"", nil},
+		{"0", nil},
+		{"-2", nil},
+		{"1,,2", nil},
+		{"two", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			got, err := parseInts(tt.list)
+			switch {
+			case tt.want == nil && err == nil:
+				t.Errorf("accepted %q as %v", tt.list, got)
+			case tt.want != nil && err != nil:
+				t.Error(err)
+			case !slices.Equal(got, tt.want):
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaledConfig(t *testing.T) {
+	base := wator.DefaultConfig()
+	base.Width, base.Height, base.NumFish, base.NumShark = 100, 100, 1000, 50
+
+	tests := []struct {
+		name          string
+		width, height int
+		fish, sharks  int
+	}{
+		{"same size", 100, 100, 1000, 50},
+		{"four times the area", 200, 200, 4000, 200},
+		{"quarter of the area", 50, 50, 250, 13}, // 12.5 sharks round away from zero.
+		{"other shape, same area", 400, 25, 1000, 50},
+		{"rounds to nearest", 33, 33, 109, 5},
+		{"too small for a shark", 5, 5, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scaledConfig(base, tt.width, tt.height)
+			want := base
+			want.Width, want.Height, want.NumFish, want.NumShark = tt.width, tt.height, tt.fish, tt.sharks
+			if got != want {
+				t.Errorf("got %dx%d with %d fish and %d sharks, want %dx%d with %d and %d",
+					got.Width, got.Height, got.NumFish, got.NumShark, tt.width, tt.height, tt.fish, tt.sharks)
+			}
+		})
+	}
+}
+
+func TestBenchBase(t *testing.T) {
+	pooled := writeFile(t, "pooled.yaml", "pool: true\nmode: checkerboard\n")
+	mapped := writeFile(t, "mapped.json", `{"terrain_map": "pond.txt"}`)
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		mode   wator.Mode
+		pool   bool
+		err    bool
+	}{
+		{"defaults", nil, "", wator.ModeBuffered, false, false},
+		{"pool flag", []string{"-pool"}, "", wator.ModeBuffered, true, false},
+		{"pool from file", nil, pooled, wator.ModeCheckerboard, true, false},
+		{"pool flag off overrides file", []string{"-pool=false"}, pooled, wator.ModeCheckerboard, false, false},
+		{"mode flag overrides file", []string{"-mode=locked"}, pooled, wator.ModeLocked, true, false},
+		{"terrain map", nil, mapped, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+			fs.String("mode", "", "")
+			fs.String("schedule", "", "")
+			fs.Bool("pool", false, "")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			base, err := benchBase(fs, tt.config)
+			switch {
+			case tt.err:
+				if err == nil {
+					t.Fatalf("accepted %+v", base)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case base.Mode != tt.mode || base.Pool != tt.pool:
+				t.Errorf("got mode %s and pool %t, want %s and %t", base.Mode, base.Pool, tt.mode, tt.pool)
+			}
+		})
+	}
+}
+
+func TestRunBenchRejectsMaps(t *testing.T) {
+	config := writeFile(t, "mapped.toml", "world_map = \"reef.png\"\n")
+	out := filepath.Join(t.TempDir(), "bench.csv")
+	err := RunBench([]string{"-config", config, "-out", out})
+	if err == nil || !strings.Contains(err.Error(), "map") {
+		t.Fatalf("got error %v, want one about maps", err)
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Error("wrote results despite the error")
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"Wa-Tor/wator"
+)
+
+// CSVLog writes one row of speed, population and timing data per chronon to
+// a CSV file, in the same format for the window and for headless runs.
+type CSVLog struct {
+	file *os.File
+	w    *csv.Writer
+}
+
+// CreateCSVLog creates the file at path and writes the header row, with busy,
+// wait and load columns for each of threads workers.
+func CreateCSVLog(path string, threads int) (*CSVLog, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	l := &CSVLog{file: file, w: csv.NewWriter(file)}
+	if err := l.w.Write(csvHeader(threads)); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Write logs the state of the world after its latest chronon. frame counts
+// the rows written so far, including this one, and tps is the rate at which
+// chronons are being run.
+func (l *CSVLog) Write(frame int, tps float64, world *wator.World) error {
+	return l.w.Write(csvRecord(frame, tps, world))
+}
+
+// Close flushes the rows written so far and closes the file.
+func (l *CSVLog) Close() error {
+	l.w.Flush()
+	if err := l.w.Error(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+// csvHeader returns the header row for a world with the given number of
+// threads.
+func csvHeader(threads int) []string {
+	header := []string{"Frame", "TPS", "ThreadCount", "Chronon", "Fish", "Sharks", "FishBorn", "SharksBorn", "Starved", "Eaten", "StepNs"}
+	for t := range threads {
+		header = append(header, fmt.Sprintf("Busy%dNs", t))
+	}
+	for t := range threads {
+		header = append(header, fmt.Sprintf("Wait%dNs", t))
+	}
+	for t := range threads {
+		header = append(header, fmt.Sprintf("Creatures%d", t))
+	}
+	return header
+}
+
+// csvRecord returns the row for the world's latest chronon.
+func csvRecord(frame int, tps float64, world *wator.World) []string {
+	st := world.Stats()
+	tm := world.Timing()
+	record := []string{
+		strconv.Itoa(frame),
+		fmt.Sprintf("%.2f", tps),
+		strconv.Itoa(world.Config().Threads),
+		strconv.Itoa(world.Chronon()),
+		strconv.Itoa(st.Fish),
+		strconv.Itoa(st.Sharks),
+		strconv.Itoa(st.FishBorn),
+		strconv.Itoa(st.SharksBorn),
+		strconv.Itoa(st.Starved),
+		strconv.Itoa(st.Eaten),
+		strconv.FormatInt(tm.Step.Nanoseconds(), 10),
+	}
+	for _, busy := range tm.Busy {
+		record = append(record, strconv.FormatInt(busy.Nanoseconds(), 10))
+	}
+	for _, wait := range tm.Wait {
+		record = append(record, strconv.FormatInt(wait.Nanoseconds(), 10))
+	}
+	for _, creatures := range tm.Creatures {
+		record = append(record, strconv.Itoa(creatures))
+	}
+	return record
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"Wa-Tor/wator"
+)
+
+// RunHeadless advances the world by n chronons as fast as possible, without
+// opening a window, and prints how long it took. With debug set, the world is
+// checked after every chronon, and the run stops early with the first broken
+// invariant. If log is not nil, a row is written to it after every chronon,
+// with the chronons per second achieved so far as its TPS.
+func RunHeadless(world *wator.World, n int, debug bool, log *CSVLog) error {
+	var busy, wait time.Duration // Summed over every worker and chronon.
+	load := make([]int, world.Config().Threads)
+	start := time.Now()
+	var err error
+	for i := range n {
+		world.Step()
+		if debug {
+			if err = world.Check(); err != nil {
+				n = i + 1 // Report only the chronons actually run.
+				break
+			}
+		}
+		tm := world.Timing()
+		for t := range tm.Busy {
+			busy += tm.Busy[t]
+			wait += tm.Wait[t]
+			load[t] += tm.Creatures[t]
+		}
+		if log != nil {
+			tps := float64(i+1) / time.Since(start).Seconds()
+			if err := log.Write(i+1, tps, world); err != nil {
+				fmt.Println("Error writing to CSV:", err)
+			}
+		}
+	}
+	elapsed := time.Since(start)
+
+	cfg := world.Config()
+	fmt.Printf("Chronons:    %d\n", n)
+	fmt.Printf("Threads:     %d\n", cfg.Threads)
+	fmt.Printf("Mode:        %s\n", cfg.Mode)
+	fmt.Printf("Schedule:    %s\n", cfg.Schedule)
+	if cfg.Pool {
+		fmt.Println("Workers:     persistent pool, synchronised by a reusable barrier")
+	} else {
+		fmt.Println("Workers:     new goroutines for every pass")
+	}
+	fmt.Printf("Seed:        %d\n", cfg.Seed)
+	if cfg.Deterministic {
+		fmt.Println("Deterministic: outcome is independent of the thread count")
+	}
+	fmt.Printf("Total time:  %v\n", elapsed)
+	if n > 0 {
+		fmt.Printf("Per chronon: %v\n", elapsed/time.Duration(n))
+		fmt.Printf("Chronons/s:  %.2f\n", float64(n)/elapsed.Seconds())
+		if busy+wait > 0 {
+			fmt.Printf("Busy:        %.1f%% of worker time (%.1f%% waiting at barriers)\n",
+				100*busy.Seconds()/(busy+wait).Seconds(), 100*wait.Seconds()/(busy+wait).Seconds())
+		}
+		if cfg.Threads > 1 {
+			fmt.Printf("Load:        busiest worker updated %.2fx the average number of creatures\n", loadImbalance(load))
+		}
+	}
+	fmt.Printf("Fish:        %d\n", world.Fish())
+	fmt.Printf("Sharks:      %d\n", world.Sharks())
+	return err
+}
+
+// loadImbalance returns the largest load in load divided by the average, so
+// 1 means the work was shared out evenly. It returns 1 if there was no work.
+func loadImbalance(load []int) float64 {
+	total, busiest := 0, 0
+	for _, l := range load {
+		total += l
+		busiest = max(busiest, l)
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(busiest) * float64(len(load)) / float64(total)
+}
@@ -0,0 +1,121 @@
+// Package cli holds what the Wa-Tor window and the headless command share:
+// reading settings from flags and config files, creating or resuming a
+// world, headless runs and benchmarks, and writing maps and snapshots. It
+// does not depend on Ebiten, so everything in it builds and runs on
+// machines without a display.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"Wa-Tor/wator"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Settings holds every parameter that can be set from a config file or the
+// command line: the simulation itself plus the window it is drawn in. The
+// headless command ignores the window, but accepts the same files.
+type Settings struct {
+	wator.Config `yaml:",inline"`
+	WindowWidth  int `json:"window_width" yaml:"window_width" toml:"window_width"`    // Window width in pixels.
+	WindowHeight int `json:"window_height" yaml:"window_height" toml:"window_height"` // Window height in pixels.
+}
+
+// DefaultSettings returns the standard simulation and window parameters.
+func DefaultSettings() Settings {
+	return Settings{
+		Config:       wator.DefaultConfig(),
+		WindowWidth:  750,
+		WindowHeight: 600,
+	}
+}
+
+// RegisterFlags binds a command-line flag to every simulation parameter in
+// s, using the current values of s as the defaults.
+func RegisterFlags(fs *flag.FlagSet, s *Settings) {
+	fs.IntVar(&s.Width, "width", s.Width, "grid width in cells")
+	fs.IntVar(&s.Height, "height", s.Height, "grid height in cells")
+	fs.IntVar(&s.NumFish, "fish", s.NumFish, "starting population of fish")
+	fs.IntVar(&s.NumShark, "sharks", s.NumShark, "starting population of sharks")
+	fs.IntVar(&s.FishBreed, "fish-breed", s.FishBreed, "steps required for fish to reproduce")
+	fs.IntVar(&s.SharkBreed, "shark-breed", s.SharkBreed, "steps required for sharks to reproduce")
+	fs.IntVar(&s.SharkStarve, "shark-starve", s.SharkStarve, "steps before a shark starves without eating")
+	fs.IntVar(&s.FishEnergy, "fish-energy", s.FishEnergy, "energy a shark gains per fish eaten (0 refills it completely)")
+	fs.IntVar(&s.Threads, "threads", s.Threads, "number of goroutines used to update the grid")
+	fs.StringVar((*string)(&s.Mode), "mode", string(s.Mode), "update strategy: buffered, checkerboard, locked or partitioned")
+	fs.StringVar((*string)(&s.Rules), "rules", string(s.Rules), "movement rules: legacy or classic")
+	fs.StringVar((*string)(&s.Neighbourhood), "neighbourhood", string(s.Neighbourhood), "cells counted as adjacent: vonneumann, moore or hex")
+	fs.StringVar((*string)(&s.Boundary), "boundary", string(s.Boundary), "grid edges: toroidal, walls, reflecting or klein")
+	fs.StringVar((*string)(&s.Schedule), "schedule", string(s.Schedule), "how the grid is shared between threads: static regions or dynamic chunks")
+	fs.BoolVar(&s.Pool, "pool", s.Pool, "keep a pool of long-lived worker goroutines instead of starting new ones every pass")
+	fs.StringVar(&s.TerrainMap, "terrain-map", s.TerrainMap, "PNG or text map of land, reefs and sea, the size of the grid")
+	fs.StringVar(&s.WorldMap, "world-map", s.WorldMap, "PNG or text map of the starting creatures and terrain, the size of the grid")
+	fs.Uint64Var(&s.Seed, "seed", s.Seed, "seed for all randomness (0 picks one at random)")
+	fs.BoolVar(&s.Deterministic, "deterministic", s.Deterministic, "make the outcome independent of the thread count (buffered mode only)")
+}
+
+// RegisterWindowFlags binds a command-line flag to the window size in s.
+func RegisterWindowFlags(fs *flag.FlagSet, s *Settings) {
+	fs.IntVar(&s.WindowWidth, "window-width", s.WindowWidth, "window width in pixels")
+	fs.IntVar(&s.WindowHeight, "window-height", s.WindowHeight, "window height in pixels")
+}
+
+// ApplyConfigFile loads the config file at path into s. Flags that were set
+// on fs take precedence over the file, so they are applied again afterwards.
+func ApplyConfigFile(fs *flag.FlagSet, path string, s *Settings) error {
+	set := map[string]string{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = f.Value.String() })
+
+	if err := loadSettings(path, s); err != nil {
+		return err
+	}
+	for name, value := range set {
+		if err := fs.Set(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSettings reads a JSON, YAML or TOML file, chosen by its extension, into
+// s. Keys missing from the file keep their current value in s; unknown keys
+// are an error, so typos are not silently ignored.
+func loadSettings(path string, s *Settings) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(s)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(s)
+	case ".toml":
+		var md toml.MetaData
+		md, err = toml.Decode(string(data), s)
+		if undecoded := md.Undecoded(); err == nil && len(undecoded) > 0 {
+			err = fmt.Errorf("unknown key %q", undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("%s: unknown config format %q (want .json, .yaml or .toml)", path, ext)
+	}
+	if err != nil && !errors.Is(err, io.EOF) { // An empty file sets nothing.
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"Wa-Tor/wator"
+)
+
+// writeFile writes data to a file called name in a fresh temporary
+// directory and returns its path.
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSettings(t *testing.T) {
+	// Every format sets the same simulation and window parameters.
+	want := DefaultSettings()
+	want.Width, want.Threads, want.Mode, want.Pool = 120, 4, wator.ModeCheckerboard, true
+	want.WindowWidth = 900
+
+	tests := []struct {
+		name, file string
+		want       Settings
+		err        string // Part of the expected error, or "" for none.
+	}{
+		{"json", `{"width": 120, "threads": 4, "mode": "checkerboard", "pool": true, "window_width": 900}`, want, ""},
+		{"yaml", "width: 120\nthreads: 4\nmode: checkerboard\npool: true\nwindow_width: 900\n", want, ""},
+		{"yml", "width: 120\nthreads: 4\nmode: checkerboard\npool: true\nwindow_width: 900\n", want, ""},
+		{"toml", "width = 120\nthreads = 4\nmode = \"checkerboard\"\npool = true\nwindow_width = 900\n", want, ""},
+		{"JSON", `{"width": 120, "threads": 4, "mode": "checkerboard", "pool": true, "window_width": 900}`, want, ""},
+		{"json", "", DefaultSettings(), ""},
+		{"yaml", "", DefaultSettings(), ""},
+		{"toml", "", DefaultSettings(), ""},
+		{"json", `{"widht": 120}`, Settings{}, "widht"},
+		{"yaml", "widht: 120\n", Settings{}, "widht"},
+		{"toml", "widht = 120\n", Settings{}, "widht"},
+		{"json", `{"width": "wide"}`, Settings{}, "width"},
+		{"ini", "width=120\n", Settings{}, "unknown config format"},
+	}
+	for _, tt := range tests {
+		name := tt.name
+		if tt.file == "" {
+			name += "/empty"
+		}
+		t.Run(name, func(t *testing.T) {
+			path := writeFile(t, "wator."+tt.name, tt.file)
+			s := DefaultSettings()
+			err := loadSettings(path, &s)
+			switch {
+			case tt.err != "":
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want one mentioning %q", err, tt.err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case s != tt.want:
+				t.Errorf("got %+v, want %+v", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSettingsMissingFile(t *testing.T) {
+	s := DefaultSettings()
+	if err := loadSettings(filepath.Join(t.TempDir(), "missing.json"), &s); err == nil {
+		t.Fatal("loaded a file that does not exist")
+	}
+}
+
+func TestApplyConfigFilePrecedence(t *testing.T) {
+	// The file sets the width, threads and window width. Flags given on the
+	// command line win over it, and everything else keeps its default.
+	path := writeFile(t, "wator.json", `{"width": 120, "threads": 4, "window_width": 900}`)
+
+	tests := []struct {
+		name  string
+		args  []string
+		check func(s Settings) bool
+	}{
+		{"file only", nil, func(s Settings) bool {
+			return s.Width == 120 && s.Threads == 4 && s.WindowWidth == 900
+		}},
+		{"flag overrides file", []string{"-threads=8"}, func(s Settings) bool {
+			return s.Width == 120 && s.Threads == 8 && s.WindowWidth == 900
+		}},
+		{"window flag overrides file", []string{"-window-width=640"}, func(s Settings) bool {
+			return s.Width == 120 && s.Threads == 4 && s.WindowWidth == 640
+		}},
+		{"flag set to its default overrides file", []string{"-width=150"}, func(s Settings) bool {
+			return s.Width == 150 && s.Threads == 4
+		}},
+		{"flag not in file", []string{"-seed=9"}, func(s Settings) bool {
+			return s.Width == 120 && s.Seed == 9
+		}},
+		{"untouched keeps default", nil, func(s Settings) bool {
+			return s.Height == DefaultSettings().Height && s.Mode == DefaultSettings().Mode
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := DefaultSettings()
+			fs := flag.NewFlagSet("wator", flag.ContinueOnError)
+			RegisterFlags(fs, &s)
+			RegisterWindowFlags(fs, &s)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := ApplyConfigFile(fs, path, &s); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(s) {
+				t.Errorf("after %v and the file, got %+v", tt.args, s)
+			}
+		})
+	}
+}
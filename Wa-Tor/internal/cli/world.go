@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"Wa-Tor/wator"
+)
+
+// OpenWorld returns the world to run. If resumePath is not empty, it
+// continues the snapshot saved there and replaces s.Config with the
+// snapshot's parameters; otherwise it creates a new world from s.Config.
+func OpenWorld(s *Settings, resumePath string) (*wator.World, error) {
+	if resumePath == "" {
+		return wator.New(s.Config)
+	}
+	world, err := wator.Load(resumePath)
+	if err != nil {
+		return nil, err
+	}
+	s.Config = world.Config()
+	return world, nil
+}
+
+// SaveSnapshot writes a snapshot of the world to path, if path is not empty.
+func SaveSnapshot(world *wator.World, path string) {
+	if path == "" {
+		return
+	}
+	if err := world.Save(path); err != nil {
+		fmt.Fprintln(os.Stderr, "Error saving snapshot:", err)
+		return
+	}
+	fmt.Printf("Saved chronon %d to %s\n", world.Chronon(), path)
+}
+
+// ExportMap writes the world to the map file at path, if path is not empty.
+func ExportMap(world *wator.World, path string) {
+	if path == "" {
+		return
+	}
+	if err := world.SaveMap(path); err != nil {
+		fmt.Fprintln(os.Stderr, "Error exporting map:", err)
+		return
+	}
+	fmt.Printf("Exported chronon %d to %s\n", world.Chronon(), path)
+}
@@ -0,0 +1,54 @@
+package wator
+
+// Boundaries.
+//
+// A boundary decides what lies beyond the edges of the grid. Every boundary
+// maps a neighbour that falls off the grid either back onto a cell at most
+// one step away, or to nothing at all, so a cell is always a neighbour of
+// its own neighbours and the update modes need no special cases at the
+// edges.
+
+// Boundary selects what happens at the edges of the grid.
+type Boundary string
+
+// Boundary conditions.
+const (
+	BoundaryToroidal   Boundary = "toroidal"   // Leaving one edge re-enters at the opposite edge.
+	BoundaryWalls      Boundary = "walls"      // Nothing lies beyond the edges.
+	BoundaryReflecting Boundary = "reflecting" // A move off an edge bounces back off it.
+	BoundaryKlein      Boundary = "klein"      // Like toroidal, but crossing the top or bottom edge mirrors the column.
+)
+
+// Boundaries lists every boundary condition.
+var Boundaries = []Boundary{BoundaryToroidal, BoundaryWalls, BoundaryReflecting, BoundaryKlein}
+
+// place maps the position (x, y), which may lie up to one cell outside the
+// grid, onto a cell of the grid under the configured boundary. It reports
+// false, leaving (x, y) unchanged, if there is no such cell.
+func (w *World) place(x, y int) (int, int, bool) {
+	width, height := w.cfg.Width, w.cfg.Height
+	switch w.cfg.Boundary {
+	case BoundaryWalls:
+		return x, y, x >= 0 && x < width && y >= 0 && y < height
+	case BoundaryReflecting:
+		return reflect(x, width), reflect(y, height), true
+	case BoundaryKlein:
+		x = (x + width) % width
+		if y < 0 || y >= height {
+			x = width - 1 - x // The bottle is glued to itself with a twist.
+		}
+		return x, (y + height) % height, true
+	}
+	return (x + width) % width, (y + height) % height, true
+}
+
+// reflect bounces a coordinate that has left the range [0, n) back into it,
+// as if it had hit a mirror at the edge.
+func reflect(c, n int) int {
+	if c < 0 {
+		c = -c
+	} else if c >= n {
+		c = 2*(n-1) - c
+	}
+	return min(max(c, 0), n-1) // A single row or column bounces back onto itself.
+}
@@ -0,0 +1,73 @@
+package wator
+
+import "testing"
+
+func TestPlace(t *testing.T) {
+	tests := []struct {
+		boundary Boundary
+		x, y     int
+		wantX    int
+		wantY    int
+		wantOK   bool
+	}{
+		{BoundaryToroidal, -1, 2, 4, 2, true},
+		{BoundaryToroidal, 5, -1, 0, 3, true},
+		{BoundaryWalls, 2, 2, 2, 2, true},
+		{BoundaryWalls, -1, 2, -1, 2, false},
+		{BoundaryWalls, 2, 4, 2, 4, false},
+		{BoundaryReflecting, -1, 2, 1, 2, true},
+		{BoundaryReflecting, 5, 4, 3, 2, true},
+		{BoundaryKlein, -1, 2, 4, 2, true},
+		{BoundaryKlein, 1, -1, 3, 3, true},
+		{BoundaryKlein, -1, 4, 0, 0, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 5, 4
+		cfg.NumFish, cfg.NumShark = 0, 0
+		cfg.Boundary = tt.boundary
+		w, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, y, ok := w.place(tt.x, tt.y)
+		if x != tt.wantX || y != tt.wantY || ok != tt.wantOK {
+			t.Errorf("%s: place(%d, %d) = (%d, %d, %v), want (%d, %d, %v)",
+				tt.boundary, tt.x, tt.y, x, y, ok, tt.wantX, tt.wantY, tt.wantOK)
+		}
+	}
+}
+
+func TestCreaturesRespectBoundary(t *testing.T) {
+	for _, boundary := range Boundaries {
+		for _, hood := range Neighbourhoods {
+			t.Run(string(boundary)+"/"+string(hood), func(t *testing.T) {
+				cfg := DefaultConfig()
+				cfg.Width, cfg.Height = 4, 4
+				cfg.NumFish, cfg.NumShark = 1, 0
+				cfg.FishBreed = 1000
+				cfg.Rules, cfg.Neighbourhood, cfg.Boundary = RulesClassic, hood, boundary
+				if cfg.Validate() != nil {
+					t.Skip("combination not supported")
+				}
+				for seed := uint64(1); seed <= 20; seed++ {
+					cfg.Seed = seed
+					w, err := New(cfg)
+					if err != nil {
+						t.Fatal(err)
+					}
+					x, y := find(t, w)
+					for range 50 {
+						w.Step()
+						nx, ny := find(t, w)
+						if !adjacent(w, x, y, nx, ny) {
+							t.Fatalf("seed %d, chronon %d: fish moved from (%d, %d) to (%d, %d), which are not neighbours",
+								seed, w.Chronon(), x, y, nx, ny)
+						}
+						x, y = nx, ny
+					}
+				}
+			})
+		}
+	}
+}
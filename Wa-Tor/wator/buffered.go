@@ -0,0 +1,177 @@
+package wator
+
+// Double-buffered update.
+//
+// A chronon is computed in two passes over the grid, with a barrier between
+// them. In the first pass every creature reads the current grid and records
+// the direction it wants to move in intents. In the second pass every cell
+// reads the current grid and intents and writes only its own entry of the
+// next grid. No cell is written by more than one goroutine, and nothing that
+// is written is read in the same pass, so any number of threads can run both
+// passes without locks.
+//
+// Conflicts are settled by a fixed rule so the outcome does not depend on the
+// order in which goroutines run:
+//   - a fish targeted by a shark is eaten and does not move;
+//   - when several creatures target the same water cell, sharks win over
+//     fish, and ties are broken in direction order around the target cell,
+//     starting from north (east for the hexagonal neighbourhood);
+//   - a creature that loses, or has nowhere to go, stays put; a fish keeps
+//     its counters unchanged, while a shark still uses up energy and ages.
+
+// directionOf returns the direction from (x, y) to the adjacent cell (nx, ny),
+// or stay if they are the same cell.
+func (w *World) directionOf(x, y, nx, ny int) int {
+	if x == nx && y == ny {
+		return stay
+	}
+	for dir := range w.dirs() {
+		if ax, ay, ok := w.neighbour(x, y, dir); ok && ax == nx && ay == ny {
+			return dir
+		}
+	}
+	return stay
+}
+
+// planCell records the move the creature at (x, y) wants to make this chronon.
+//
+// Fish pick a random direction and only move into water. Sharks head for an
+// adjacent fish if there is one, otherwise they behave like fish. A shark with
+// no fish to eat and no energy left does not move and is removed by
+// resolveCell.
+func (w *World) planCell(wk *worker, x, y int) {
+	rect := w.at(x, y)
+	dir := stay
+	if rect.Kind != Water {
+		wk.creatures++
+		newX, newY := x, y
+		if rect.Kind == Shark {
+			newX, newY = w.checkAdjacent(wk, x, y)
+		}
+		if newX == x && newY == y && !w.starves(rect) {
+			newX, newY = w.moveEntity(wk, x, y)
+			if w.at(newX, newY).Kind != Water {
+				newX, newY = x, y
+			}
+		}
+		dir = w.directionOf(x, y, newX, newY)
+	}
+	w.intents[w.index(x, y)] = int8(dir)
+}
+
+// intent returns the direction the creature at (x, y) plans to move in.
+func (w *World) intent(x, y int) int {
+	return int(w.intents[w.index(x, y)])
+}
+
+// movesTo reports whether the creature at (sx, sy) plans to move into (x, y).
+func (w *World) movesTo(sx, sy, x, y int) bool {
+	dir := w.intent(sx, sy)
+	if dir == stay {
+		return false
+	}
+	tx, ty, _ := w.neighbour(sx, sy, dir)
+	return tx == x && ty == y
+}
+
+// eaten reports whether a shark plans to move onto the fish at (x, y).
+func (w *World) eaten(x, y int) bool {
+	for dir := range w.dirs() {
+		sx, sy, ok := w.neighbour(x, y, dir)
+		if ok && w.at(sx, sy).Kind == Shark && w.movesTo(sx, sy, x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// arrival returns the direction, seen from (x, y), of the creature that wins
+// the move into (x, y) this chronon, or stay if no creature moves there.
+func (w *World) arrival(x, y int) int {
+	winner := stay
+	for dir := range w.dirs() {
+		sx, sy, ok := w.neighbour(x, y, dir)
+		if !ok || !w.movesTo(sx, sy, x, y) {
+			continue
+		}
+		if w.at(sx, sy).Kind == Shark {
+			return dir // Sharks take precedence over fish.
+		}
+		if winner == stay && !w.eaten(sx, sy) {
+			winner = dir
+		}
+	}
+	return winner
+}
+
+// leaves reports whether the creature at (x, y) wins the move it planned.
+func (w *World) leaves(x, y int) bool {
+	dir := w.intent(x, y)
+	if dir == stay {
+		return false
+	}
+	tx, ty, _ := w.neighbour(x, y, dir)
+	from := w.arrival(tx, ty)
+	if from == stay {
+		return false
+	}
+	sx, sy, _ := w.neighbour(tx, ty, from)
+	return sx == x && sy == y
+}
+
+// breedThreshold returns the breeding threshold for creatures of the given kind.
+func (w *World) breedThreshold(kind Kind) int {
+	if kind == Shark {
+		return w.cfg.SharkBreed
+	}
+	return w.cfg.FishBreed
+}
+
+// starves reports whether the shark in rect runs out of energy this chronon
+// unless it eats.
+func (w *World) starves(rect *Cell) bool {
+	return rect.Kind == Shark && rect.Starve-1 <= 0
+}
+
+// resolveCell writes the state of (x, y) for the next chronon to the next grid.
+//
+// The counters follow the in-place rules in moveFish and moveShark: a fish
+// ages when it moves, a shark ages and uses up one unit of energy every
+// chronon, and a creature that has reached its breeding threshold leaves a
+// newborn behind when it moves.
+func (w *World) resolveCell(wk *worker, x, y int) {
+	rect := w.at(x, y)
+	next := *rect
+
+	if from := w.arrival(x, y); from != stay {
+		// A creature moves in, eating any fish that was here.
+		sx, sy, _ := w.neighbour(x, y, from)
+		next = *w.at(sx, sy)
+		next.Breed++
+		if next.Kind == Shark {
+			next.Starve--
+			if rect.Kind == Fish {
+				next.Starve = w.feed(next.Starve)
+				wk.events.Eaten++
+			}
+		}
+		if next.Breed >= w.breedThreshold(next.Kind) {
+			next.Breed = 0 // Offspring is left behind in the source cell.
+		}
+	} else if w.leaves(x, y) {
+		// The creature moves out, leaving offspring or water behind.
+		next = Cell{}
+		if rect.Breed+1 >= w.breedThreshold(rect.Kind) {
+			next = w.newborn(rect.Kind, 0)
+			wk.born(rect.Kind)
+		}
+	} else if w.starves(rect) {
+		next = Cell{} // Shark starves.
+		wk.events.Starved++
+	} else if rect.Kind == Shark {
+		next.Starve--
+		next.Breed++
+	}
+
+	w.next[w.index(x, y)] = next
+}
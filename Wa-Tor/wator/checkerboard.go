@@ -0,0 +1,50 @@
+package wator
+
+// Checkerboard update.
+//
+// A creature only ever reads or writes cells at most one step away from the
+// cell being updated. If the grid is cut into tiles at least two cells wide
+// and the tiles are coloured in a repeating 2x2 pattern, any two tiles of the
+// same colour are separated by a whole tile of another colour, so their
+// neighbourhoods never overlap. All tiles of one colour can therefore be
+// updated in place at the same time, using the ordinary updateCell, as long
+// as the colours are run one after another with a barrier in between.
+
+// checkerboardTiles splits a width x height grid into tiles and groups them by
+// colour, returning one slice of tiles per phase.
+//
+// Each axis is cut into an even number of tiles so the colouring also holds
+// where the torus wraps around, with enough tiles per colour to keep the
+// given number of threads busy. A grid too small to cut this way is returned
+// as a single tile in a single phase.
+func checkerboardTiles(threads, width, height int) [][]region {
+	perAxis := 1
+	for perAxis*perAxis < threads {
+		perAxis++
+	}
+	tilesX := evenTiles(2*perAxis, width)
+	tilesY := evenTiles(2*perAxis, height)
+	if tilesX < 2 || tilesY < 2 {
+		return [][]region{{{x0: 0, x1: width, y0: 0, y1: height}}}
+	}
+
+	phases := make([][]region, 4)
+	for tx := 0; tx < tilesX; tx++ {
+		for ty := 0; ty < tilesY; ty++ {
+			colour := tx%2 + 2*(ty%2)
+			phases[colour] = append(phases[colour], region{
+				x0: tx * width / tilesX, x1: (tx + 1) * width / tilesX,
+				y0: ty * height / tilesY, y1: (ty + 1) * height / tilesY,
+			})
+		}
+	}
+	return phases
+}
+
+// evenTiles returns the number of tiles to cut an axis of length dim into:
+// want rounded down to an even number, reduced so no tile is narrower than
+// two cells.
+func evenTiles(want, dim int) int {
+	n := min(want, dim/2)
+	return n - n%2
+}
@@ -0,0 +1,153 @@
+package wator
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Mode selects how a World shares the work of a chronon between threads.
+type Mode string
+
+// Update strategies.
+const (
+	ModeBuffered     Mode = "buffered"     // Read the current grid and write the next one, then swap.
+	ModeCheckerboard Mode = "checkerboard" // Update in place, one tile colour at a time.
+	ModeLocked       Mode = "locked"       // Update in place, locking the tiles each move touches.
+	ModePartitioned  Mode = "partitioned"  // Update in place; racy across region borders.
+)
+
+// Modes lists every update strategy.
+var Modes = []Mode{ModeBuffered, ModeCheckerboard, ModeLocked, ModePartitioned}
+
+// Rules selects how creatures choose where to move.
+type Rules string
+
+// Movement rules.
+const (
+	// RulesLegacy picks one random direction and stays put if that cell is
+	// taken; sharks prefer fish to the east, then west, south and north.
+	RulesLegacy Rules = "legacy"
+	// RulesClassic follows Dewdney: fish pick uniformly among the free
+	// neighbouring cells, sharks pick uniformly among neighbouring fish and
+	// otherwise among free cells.
+	RulesClassic Rules = "classic"
+)
+
+// RuleSets lists every set of movement rules.
+var RuleSets = []Rules{RulesLegacy, RulesClassic}
+
+// Config holds the parameters of a simulation.
+//
+// The struct tags give the key names used in JSON, YAML and TOML config files.
+type Config struct {
+	Width       int    `json:"width" yaml:"width" toml:"width"`                      // Grid width in cells.
+	Height      int    `json:"height" yaml:"height" toml:"height"`                   // Grid height in cells.
+	NumFish     int    `json:"num_fish" yaml:"num_fish" toml:"num_fish"`             // Starting population of fish.
+	NumShark    int    `json:"num_shark" yaml:"num_shark" toml:"num_shark"`          // Starting population of sharks.
+	FishBreed   int    `json:"fish_breed" yaml:"fish_breed" toml:"fish_breed"`       // Steps required for fish to reproduce.
+	SharkBreed  int    `json:"shark_breed" yaml:"shark_breed" toml:"shark_breed"`    // Steps required for sharks to reproduce.
+	SharkStarve int    `json:"shark_starve" yaml:"shark_starve" toml:"shark_starve"` // Steps before a shark starves without eating; also its maximum energy.
+	FishEnergy  int    `json:"fish_energy" yaml:"fish_energy" toml:"fish_energy"`    // Energy a shark gains per fish eaten; 0 refills it completely.
+	Threads     int    `json:"threads" yaml:"threads" toml:"threads"`                // Number of goroutines used to update the grid.
+	Mode        Mode   `json:"mode" yaml:"mode" toml:"mode"`                         // Update strategy.
+	Rules       Rules  `json:"rules" yaml:"rules" toml:"rules"`                      // Movement rules.
+	Seed        uint64 `json:"seed" yaml:"seed" toml:"seed"`                         // Seed for all randomness; 0 picks one at random.
+
+	// Deterministic draws each cell's random numbers from a generator keyed
+	// on the seed, chronon and cell position rather than from a per-thread
+	// stream, so the outcome does not depend on Threads. It requires
+	// ModeBuffered, whose conflict resolution is already order-independent.
+	Deterministic bool `json:"deterministic" yaml:"deterministic" toml:"deterministic"`
+
+	// Neighbourhood selects the cells a creature can see and move to. The
+	// hexagonal neighbourhood needs an even Height on a torus so that rows
+	// keep alternating where the grid wraps around.
+	Neighbourhood Neighbourhood `json:"neighbourhood" yaml:"neighbourhood" toml:"neighbourhood"`
+
+	// Boundary selects what lies beyond the edges of the grid. Mirroring a
+	// hexagonal grid would break its row offsets, so the hexagonal
+	// neighbourhood supports only the toroidal and walls boundaries.
+	Boundary Boundary `json:"boundary" yaml:"boundary" toml:"boundary"`
+
+	// TerrainMap is the path of a map file, a PNG image or a text file with
+	// one pixel or character per cell, giving the land, reefs and open sea
+	// of the grid; any creatures in it are ignored. It must match the size
+	// of the grid. Empty means open sea everywhere.
+	TerrainMap string `json:"terrain_map" yaml:"terrain_map" toml:"terrain_map"`
+
+	// WorldMap is the path of a map file giving both the terrain and the
+	// starting fish and sharks, in place of NumFish and NumShark and
+	// TerrainMap. World.SaveMap writes such files.
+	WorldMap string `json:"world_map" yaml:"world_map" toml:"world_map"`
+
+	// Schedule selects how the grid is shared between the threads: in a
+	// fixed region per thread, or in small chunks claimed by whichever
+	// thread is free. A dynamic run is reproducible for a seed only with
+	// one thread or with Deterministic set.
+	Schedule Schedule `json:"schedule" yaml:"schedule" toml:"schedule"`
+
+	// Pool runs the workers on long-lived goroutines that meet at a
+	// reusable barrier between passes, instead of starting new goroutines
+	// for every pass. A world with a pool must be closed with World.Close
+	// to stop them.
+	Pool bool `json:"pool" yaml:"pool" toml:"pool"`
+}
+
+// DefaultConfig returns the standard simulation parameters.
+func DefaultConfig() Config {
+	return Config{
+		Width:       150,
+		Height:      150,
+		NumFish:     1000,
+		NumShark:    15,
+		FishBreed:   5,
+		SharkBreed:  10,
+		SharkStarve: 7,
+		Threads:     1,
+		Mode:        ModeBuffered,
+		Rules:       RulesLegacy,
+
+		Neighbourhood: VonNeumann,
+		Boundary:      BoundaryToroidal,
+		Schedule:      ScheduleStatic,
+	}
+}
+
+// Validate reports the first parameter in c that cannot be simulated.
+func (c Config) Validate() error {
+	switch {
+	case c.Width < 1 || c.Height < 1:
+		return fmt.Errorf("grid must be at least 1x1, got %dx%d", c.Width, c.Height)
+	case c.NumFish < 0 || c.NumShark < 0:
+		return fmt.Errorf("populations must not be negative, got %d fish and %d sharks", c.NumFish, c.NumShark)
+	case c.WorldMap == "" && c.NumFish+c.NumShark > c.Width*c.Height: // A world map replaces the populations.
+		return fmt.Errorf("%d fish and %d sharks do not fit in a %dx%d grid", c.NumFish, c.NumShark, c.Width, c.Height)
+	case c.FishBreed < 1 || c.SharkBreed < 1:
+		return fmt.Errorf("breeding times must be at least 1, got %d for fish and %d for sharks", c.FishBreed, c.SharkBreed)
+	case c.SharkStarve < 1:
+		return fmt.Errorf("shark starvation time must be at least 1, got %d", c.SharkStarve)
+	case c.FishEnergy < 0:
+		return fmt.Errorf("energy per fish must not be negative, got %d", c.FishEnergy)
+	case c.Threads < 1:
+		return fmt.Errorf("threads must be at least 1, got %d", c.Threads)
+	case !slices.Contains(Modes, c.Mode):
+		return fmt.Errorf("unknown mode %q", c.Mode)
+	case !slices.Contains(RuleSets, c.Rules):
+		return fmt.Errorf("unknown rules %q", c.Rules)
+	case !slices.Contains(Neighbourhoods, c.Neighbourhood):
+		return fmt.Errorf("unknown neighbourhood %q", c.Neighbourhood)
+	case !slices.Contains(Boundaries, c.Boundary):
+		return fmt.Errorf("unknown boundary %q", c.Boundary)
+	case !slices.Contains(Schedules, c.Schedule):
+		return fmt.Errorf("unknown schedule %q", c.Schedule)
+	case c.Neighbourhood == Hex && c.Boundary != BoundaryToroidal && c.Boundary != BoundaryWalls:
+		return fmt.Errorf("the %s neighbourhood does not support the %s boundary", Hex, c.Boundary)
+	case c.Neighbourhood == Hex && c.Boundary == BoundaryToroidal && c.Height%2 != 0:
+		return fmt.Errorf("the %s neighbourhood needs an even grid height to wrap around, got %d", Hex, c.Height)
+	case c.TerrainMap != "" && c.WorldMap != "":
+		return fmt.Errorf("a world map already holds the terrain, so a terrain map cannot be given too")
+	case c.Deterministic && c.Mode != ModeBuffered:
+		return fmt.Errorf("deterministic runs need the %s mode, not %s", ModeBuffered, c.Mode)
+	}
+	return nil
+}
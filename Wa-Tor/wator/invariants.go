@@ -0,0 +1,100 @@
+package wator
+
+import "fmt"
+
+// Invariant checking.
+//
+// Check scans the whole grid, so it is meant for debugging rather than for
+// every run. It catches the kinds of bug that otherwise go unnoticed: water
+// left holding the counters of a creature that moved away, counters run out
+// of range, creatures on terrain they cannot enter, and creatures that were
+// copied or lost by a move without a birth or death being recorded.
+
+// InvariantError describes the first invariant found broken by Check.
+type InvariantError struct {
+	Chronon int    // Number of steps taken when the check ran.
+	X, Y    int    // Cell breaking the invariant, or -1 if it concerns the whole grid.
+	Cell    Cell   // State of the cell, if there is one.
+	Problem string // What is wrong.
+}
+
+func (e *InvariantError) Error() string {
+	if e.X < 0 {
+		return fmt.Sprintf("chronon %d: %s", e.Chronon, e.Problem)
+	}
+	return fmt.Sprintf("chronon %d: cell (%d, %d) holding %s with starve %d and breed %d: %s",
+		e.Chronon, e.X, e.Y, e.Cell.Kind, e.Cell.Starve, e.Cell.Breed, e.Problem)
+}
+
+// Check verifies that the world is in a consistent state and returns an
+// *InvariantError for the first problem found, or nil. Cells are checked in
+// the order x, then y, before the population as a whole:
+//
+//   - water carries no breeding or starvation counters;
+//   - no counter is negative, fish have no starvation counter, and a shark's
+//     energy lies between 1 and SharkStarve;
+//   - every creature stands on terrain that admits it;
+//   - the number of fish and sharks on the grid matches the population
+//     reported by Stats, which follows only from the births and deaths
+//     recorded while updating. More creatures than that means one was
+//     duplicated, fewer means one was lost.
+//
+// In the partitioned mode with more than one thread, racing updates are
+// expected to break the last invariant.
+func (w *World) Check() error {
+	for x := range w.cfg.Width {
+		for y := range w.cfg.Height {
+			if problem := w.checkCell(x, y); problem != "" {
+				return &InvariantError{Chronon: w.chronon, X: x, Y: y, Cell: *w.at(x, y), Problem: problem}
+			}
+		}
+	}
+
+	populations := []struct {
+		name      string
+		got, want int
+	}{
+		{"fish", w.count(Fish), w.stats.Fish},
+		{"sharks", w.count(Shark), w.stats.Sharks},
+	}
+	for _, p := range populations {
+		if p.got != p.want {
+			problem := "lost"
+			if p.got > p.want {
+				problem = "duplicated"
+			}
+			return &InvariantError{Chronon: w.chronon, X: -1, Y: -1, Problem: fmt.Sprintf(
+				"%d %s on the grid but %d expected from births and deaths: %d %s",
+				p.got, p.name, p.want, abs(p.got-p.want), problem)}
+		}
+	}
+	return nil
+}
+
+// checkCell returns what is wrong with the cell at (x, y), or "" if nothing is.
+func (w *World) checkCell(x, y int) string {
+	c := w.at(x, y)
+	switch {
+	case c.Kind == Water && (c.Breed != 0 || c.Starve != 0):
+		return "water has leftover counters"
+	case c.Kind > Shark:
+		return "unknown kind"
+	case c.Breed < 0 || c.Starve < 0:
+		return "negative counter"
+	case c.Kind == Fish && c.Starve != 0:
+		return "fish has a starvation counter"
+	case c.Kind == Shark && (c.Starve < 1 || c.Starve > w.cfg.SharkStarve):
+		return fmt.Sprintf("shark energy outside 1 to %d", w.cfg.SharkStarve)
+	case c.Kind != Water && !w.terrainAt(x, y).admits(c.Kind):
+		return fmt.Sprintf("%s on %s", c.Kind, w.terrainAt(x, y))
+	}
+	return ""
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
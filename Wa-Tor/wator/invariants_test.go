@@ -0,0 +1,83 @@
+package wator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStepKeepsInvariants(t *testing.T) {
+	for _, mode := range Modes {
+		for _, rules := range RuleSets {
+			for _, hood := range Neighbourhoods {
+				for _, boundary := range Boundaries {
+					edit := func(cfg *Config) {
+						cfg.Width, cfg.Height = 24, 24
+						cfg.NumFish, cfg.NumShark = 150, 20
+						cfg.FishEnergy = 3
+						cfg.Mode, cfg.Rules, cfg.Neighbourhood, cfg.Boundary = mode, rules, hood, boundary
+						cfg.Threads = 4
+						if mode == ModePartitioned {
+							cfg.Threads = 1 // Racing threads may lose or duplicate creatures.
+						}
+					}
+					if testConfig(edit).Validate() != nil {
+						continue
+					}
+					t.Run(string(mode)+"/"+string(rules)+"/"+string(hood)+"/"+string(boundary), func(t *testing.T) {
+						w := newTestWorld(t, edit)
+						for range 50 {
+							w.Step()
+							if err := w.Check(); err != nil {
+								t.Fatal(err)
+							}
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestCheckFindsFirstViolation(t *testing.T) {
+	tests := []struct {
+		name   string
+		x, y   int
+		cell   Cell
+		wantX  int // -1 for a problem with the population as a whole.
+		onReef bool
+	}{
+		{"stale breed on water", 3, 1, Cell{Breed: 2}, 3, false},
+		{"stale starve on water", 0, 4, Cell{Starve: 1}, 0, false},
+		{"negative breed", 2, 2, Cell{Kind: Fish, Breed: -1}, 2, false},
+		{"fish with energy", 4, 0, Cell{Kind: Fish, Starve: 3}, 4, false},
+		{"shark out of energy", 1, 3, Cell{Kind: Shark}, 1, false},
+		{"overfed shark", 1, 3, Cell{Kind: Shark, Starve: 101}, 1, false},
+		{"shark on reef", 2, 2, Cell{Kind: Shark, Starve: 5}, 2, true},
+		{"duplicated fish", 3, 3, Cell{Kind: Fish}, -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := ".....\n.....\n.....\n.....\n.....\n"
+			if tt.onReef {
+				rows = ".....\n.....\n..~..\n.....\n.....\n"
+			}
+			w := terrainWorld(t, rows, 5, 5)
+			w.Step()
+			if err := w.Check(); err != nil {
+				t.Fatalf("empty world: %v", err)
+			}
+
+			*w.at(tt.x, tt.y) = tt.cell
+			var ie *InvariantError
+			if err := w.Check(); !errors.As(err, &ie) {
+				t.Fatalf("Check() = %v, want an *InvariantError", err)
+			}
+			if ie.X != tt.wantX || ie.Chronon != 1 {
+				t.Errorf("Check() = %v, want a problem at x=%d in chronon 1", ie, tt.wantX)
+			}
+			if tt.wantX >= 0 && (ie.Y != tt.y || ie.Cell != tt.cell) {
+				t.Errorf("Check() reported cell (%d, %d) = %+v, want (%d, %d) = %+v", ie.X, ie.Y, ie.Cell, tt.x, tt.y, tt.cell)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package wator
+
+import "slices"
+
+// Per-tile locking update.
+//
+// The grid is covered by square tiles, each guarded by its own mutex. Before
+// updating a cell, a goroutine locks every tile that the move could touch:
+// the tile holding the creature and the tiles holding its neighbours,
+// which covers moveEntity, checkAdjacent and eatFish as well as the offspring
+// left behind when breeding. Locks are always taken in ascending tile order,
+// so two goroutines can never wait on each other. Most cells lie inside a
+// single tile and need only one lock.
+
+const lockTileSize = 10 // Width and height, in cells, of the tile guarded by each mutex.
+
+// lockTiles returns the number of lock tiles needed to cover dim cells.
+func lockTiles(dim int) int {
+	return (dim + lockTileSize - 1) / lockTileSize
+}
+
+// tileOf returns the index of the lock tile containing (x, y).
+func (w *World) tileOf(x, y int) int {
+	return (x/lockTileSize)*lockTiles(w.cfg.Height) + y/lockTileSize
+}
+
+// lockedUpdateCell updates a single cell while holding the locks of every tile
+// in its neighbourhood.
+func (w *World) lockedUpdateCell(wk *worker, x, y int) {
+	var buf [maxDirs + 1]int
+	tiles := buf[:w.dirs()+1]
+	tiles[0] = w.tileOf(x, y)
+	for dir := range w.dirs() {
+		nx, ny, _ := w.neighbour(x, y, dir) // Beyond a wall, (x, y) itself.
+		tiles[dir+1] = w.tileOf(nx, ny)
+	}
+	slices.Sort(tiles)
+	held := slices.Compact(tiles) // Ascending order, each tile once.
+
+	for _, t := range held {
+		w.locks[t].Lock()
+	}
+	w.updateCell(wk, x, y)
+	for i := len(held) - 1; i >= 0; i-- {
+		w.locks[held[i]].Unlock()
+	}
+}
@@ -0,0 +1,94 @@
+package wator
+
+// Neighbourhoods.
+//
+// A neighbourhood lists the cells a creature can see and move to, as offsets
+// from its own cell, numbered going round the cell. Every neighbour lies at most one
+// cell away along each axis, which the checkerboard and locked updates rely
+// on. What lies beyond the edges of the grid is left to the Boundary.
+//
+// The hexagonal neighbourhood uses "odd-r" offset coordinates: the grid is
+// stored as ordinary rows, and every odd row is drawn shifted half a cell to
+// the east, so a cell touches two cells in the row above, two in the row
+// below and one on either side. The offsets of the diagonal neighbours
+// therefore depend on whether the row is even or odd.
+
+// Neighbourhood selects which cells count as adjacent.
+type Neighbourhood string
+
+// Neighbourhoods.
+const (
+	VonNeumann Neighbourhood = "vonneumann" // The four cells sharing an edge.
+	Moore      Neighbourhood = "moore"      // The eight cells sharing an edge or a corner.
+	Hex        Neighbourhood = "hex"        // The six cells of a hexagonal grid in odd-r offset layout.
+)
+
+// Neighbourhoods lists every neighbourhood.
+var Neighbourhoods = []Neighbourhood{VonNeumann, Moore, Hex}
+
+// Directions of the von Neumann neighbourhood, in the order used to break
+// ties between creatures.
+const (
+	stay  = -1
+	north = 0
+	east  = 1
+	south = 2
+	west  = 3
+)
+
+// maxDirs is the largest number of directions of any neighbourhood.
+const maxDirs = 8
+
+// offset is the position of a neighbouring cell relative to the cell itself.
+type offset struct{ dx, dy int }
+
+// topology describes the directions of one neighbourhood.
+type topology struct {
+	offsets [2][]offset // Neighbour offsets for cells in even and odd rows.
+	legacy  []int       // Order in which the legacy rules look for fish.
+}
+
+var topologies = map[Neighbourhood]topology{
+	VonNeumann: {
+		offsets: sameRows([]offset{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}),
+		legacy:  []int{east, west, south, north},
+	},
+	Moore: {
+		// N, NE, E, SE, S, SW, W, NW.
+		offsets: sameRows([]offset{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}),
+		legacy:  []int{2, 6, 4, 0, 3, 5, 1, 7},
+	},
+	Hex: {
+		// E, SE, SW, W, NW, NE.
+		offsets: [2][]offset{
+			{{1, 0}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}},
+			{{1, 0}, {1, 1}, {0, 1}, {-1, 0}, {0, -1}, {1, -1}},
+		},
+		legacy: []int{0, 3, 1, 2, 5, 4},
+	},
+}
+
+// sameRows returns offsets for a neighbourhood that is the same in every row.
+func sameRows(offsets []offset) [2][]offset {
+	return [2][]offset{offsets, offsets}
+}
+
+// dirs returns the number of directions in the world's neighbourhood.
+func (w *World) dirs() int {
+	return len(w.topo.offsets[0])
+}
+
+// neighbour returns the coordinates of the cell next to (x, y) in direction
+// dir, applying the boundary at the edges of the grid. If the boundary
+// leaves no cell there, it returns (x, y) itself and false.
+func (w *World) neighbour(x, y, dir int) (int, int, bool) {
+	if dir == stay {
+		return x, y, true
+	}
+	off := w.topo.offsets[y&1][dir]
+	nx, ny, ok := w.place(x+off.dx, y+off.dy)
+	if !ok {
+		return x, y, false
+	}
+	return nx, ny, true
+}
@@ -0,0 +1,41 @@
+package wator
+
+import "testing"
+
+func TestNeighbourhoods(t *testing.T) {
+	tests := []struct {
+		hood Neighbourhood
+		x, y int
+		want [][2]int // Neighbours of (x, y) in direction order.
+	}{
+		{VonNeumann, 0, 0, [][2]int{{0, 5}, {1, 0}, {0, 1}, {5, 0}}},
+		{Moore, 2, 2, [][2]int{{2, 1}, {3, 1}, {3, 2}, {3, 3}, {2, 3}, {1, 3}, {1, 2}, {1, 1}}},
+		{Hex, 2, 2, [][2]int{{3, 2}, {2, 3}, {1, 3}, {1, 2}, {1, 1}, {2, 1}}},
+		{Hex, 2, 3, [][2]int{{3, 3}, {3, 4}, {2, 4}, {1, 3}, {2, 2}, {3, 2}}},
+		{Hex, 5, 5, [][2]int{{0, 5}, {0, 0}, {5, 0}, {4, 5}, {5, 4}, {0, 4}}},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 6, 6
+		cfg.NumFish, cfg.NumShark = 0, 0
+		cfg.Neighbourhood = tt.hood
+		w, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.dirs() != len(tt.want) {
+			t.Fatalf("%s: %d directions, want %d", tt.hood, w.dirs(), len(tt.want))
+		}
+		for dir, want := range tt.want {
+			nx, ny, _ := w.neighbour(tt.x, tt.y, dir)
+			if nx != want[0] || ny != want[1] {
+				t.Errorf("%s: neighbour of (%d, %d) in direction %d = (%d, %d), want %v",
+					tt.hood, tt.x, tt.y, dir, nx, ny, want)
+			}
+			if w.directionOf(nx, ny, tt.x, tt.y) == stay {
+				t.Errorf("%s: (%d, %d) is not a neighbour of its neighbour (%d, %d)",
+					tt.hood, tt.x, tt.y, nx, ny)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package wator
+
+import "sync"
+
+// Worker pool.
+//
+// Without a pool, every pass of every chronon starts one goroutine per
+// worker and waits for them on a fresh WaitGroup. With Config.Pool set, the
+// world instead starts one goroutine per worker the first time it steps and
+// keeps them for its whole life. Between passes they park at a reusable
+// barrier, which the goroutine calling Step also joins: once to release the
+// workers into a pass, and once more to wait for all of them to finish it.
+
+// barrier is a reusable barrier for a fixed number of goroutines, built from
+// a mutex and two turnstiles in the manner of Lab/ReusableBarrier. No
+// goroutine leaves wait until all of them have arrived, and none can arrive
+// for the next round before all of them have left the current one.
+type barrier struct {
+	n       int
+	mu      sync.Mutex
+	arrived int           // Goroutines in the current round, guarded by mu.
+	in      chan struct{} // Turnstile opened once every goroutine has arrived.
+	out     chan struct{} // Turnstile opened once every goroutine is through the first.
+}
+
+// newBarrier returns a barrier for n goroutines.
+func newBarrier(n int) *barrier {
+	return &barrier{n: n, in: make(chan struct{}, n), out: make(chan struct{}, n)}
+}
+
+// wait blocks until all n goroutines have called wait.
+func (b *barrier) wait() {
+	b.mu.Lock()
+	b.arrived++
+	if b.arrived == b.n { // Last to arrive lets everyone through.
+		for range b.n {
+			b.in <- struct{}{}
+		}
+	}
+	b.mu.Unlock()
+	<-b.in
+
+	b.mu.Lock()
+	b.arrived--
+	if b.arrived == 0 { // Last to leave resets the barrier for the next round.
+		for range b.n {
+			b.out <- struct{}{}
+		}
+	}
+	b.mu.Unlock()
+	<-b.out
+}
+
+// pool holds the long-lived goroutines that run the workers of a world.
+type pool struct {
+	barrier *barrier // Shared by every worker and the goroutine calling Step.
+	pass    *pass    // Pass to run when the barrier next opens, or nil to stop.
+}
+
+// startPool starts one goroutine for each worker, parked until the first
+// pass.
+func (w *World) startPool() {
+	pl := &pool{barrier: newBarrier(w.cfg.Threads + 1)}
+	for t := range w.cfg.Threads {
+		go func() {
+			for {
+				pl.barrier.wait() // Wait for the next pass.
+				p := pl.pass
+				if p == nil {
+					return
+				}
+				if t < len(p.regions) {
+					w.work(t, p)
+				}
+				pl.barrier.wait() // Wait for the others to finish it.
+			}
+		}()
+	}
+	w.pool = pl
+}
+
+// run has the pool's workers carry out pass p and returns once all of them
+// are done.
+func (pl *pool) run(p *pass) {
+	pl.pass = p
+	pl.barrier.wait()
+	pl.barrier.wait()
+}
+
+// Close stops the goroutines of the world's worker pool, if it has one. A
+// world created with Config.Pool keeps its goroutines, and so itself, alive
+// until it is closed. Stepping a closed world starts a new pool. Close does
+// nothing for a world without a pool.
+func (w *World) Close() {
+	if w.pool == nil {
+		return
+	}
+	w.pool.pass = nil
+	w.pool.barrier.wait() // Release the workers to find there is nothing to do.
+	w.pool = nil
+}
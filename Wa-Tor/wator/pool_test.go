@@ -0,0 +1,94 @@
+package wator
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBarrierIsReusable(t *testing.T) {
+	const n, rounds = 5, 200
+	b := newBarrier(n)
+	var arrived atomic.Int64
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range int64(rounds) {
+				arrived.Add(1)
+				b.wait()
+				// Everyone has arrived for round r, and nobody can be more
+				// than one round ahead.
+				if got := arrived.Load(); got < n*(r+1) || got >= n*(r+2) {
+					t.Errorf("round %d: %d arrivals after the barrier, want %d to %d", r, got, n*(r+1), n*(r+2)-1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPoolMatchesSpawning(t *testing.T) {
+	for _, mode := range []Mode{ModeBuffered, ModeCheckerboard} {
+		t.Run(string(mode), func(t *testing.T) {
+			var worlds [2]*World
+			for i, pooled := range []bool{false, true} {
+				worlds[i] = newTestWorld(t, func(cfg *Config) {
+					cfg.Mode, cfg.Threads, cfg.Seed, cfg.Pool = mode, 4, 3, pooled
+				})
+				for range 30 {
+					worlds[i].Step()
+				}
+			}
+			if i := sameCells(worlds[0], worlds[1]); i >= 0 {
+				t.Fatalf("cell %d is %+v when spawning goroutines but %+v with a pool", i, worlds[0].cells[i], worlds[1].cells[i])
+			}
+		})
+	}
+}
+
+func TestCloseStopsPool(t *testing.T) {
+	before := runtime.NumGoroutine()
+	w := newTestWorld(t, func(cfg *Config) { cfg.Threads, cfg.Pool = 4, true })
+	w.Step()
+	w.Close()
+	w.Close() // Closing again does nothing.
+
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running after Close, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// BenchmarkPool compares starting goroutines for every pass with keeping a
+// pool of them, on a grid small enough for the cost of starting them to
+// show and on the default grid.
+func BenchmarkPool(b *testing.B) {
+	for _, size := range []int{16, 150} {
+		for _, pooled := range []bool{false, true} {
+			for _, threads := range []int{1, 2, 4, 8} {
+				name := "spawn"
+				if pooled {
+					name = "pool"
+				}
+				b.Run(fmt.Sprintf("%dx%d/%s/threads=%d", size, size, name, threads), func(b *testing.B) {
+					w := newTestWorld(b, func(cfg *Config) {
+						cfg.Width, cfg.Height = size, size
+						cfg.NumFish, cfg.NumShark = size*size/4, size*size/40
+						cfg.Threads, cfg.Pool = threads, pooled
+					})
+					b.ResetTimer()
+					for range b.N {
+						w.Step()
+					}
+				})
+			}
+		}
+	}
+}
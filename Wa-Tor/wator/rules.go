@@ -0,0 +1,194 @@
+package wator
+
+import "math/rand/v2"
+
+// placeEntities randomly places a specified number of entities (fish or sharks)
+// on the grid, drawing positions from rng. Entities are placed only in empty
+// (water) cells of open sea.
+func (w *World) placeEntities(rng *rand.Rand, num int, kind Kind) {
+	count := 0
+	for count < num {
+		x := rng.IntN(w.cfg.Width)
+		y := rng.IntN(w.cfg.Height)
+
+		rect := w.at(x, y)
+
+		if rect.Kind == Water && w.terrainAt(x, y) == Sea {
+			rect.Kind = kind
+			if kind == Shark {
+				rect.Starve = w.cfg.SharkStarve // Initialize shark's energy.
+			}
+			rect.Breed = 0 // Initialize breeding counter.
+			count++
+		}
+	}
+}
+
+// updateCell updates the state of a single cell based on its contents.
+//
+// A creature that has already acted this chronon, because it moved here from
+// a cell updated earlier or was born this chronon, is left alone.
+func (w *World) updateCell(wk *worker, x, y int) {
+	rect := w.at(x, y)
+	if rect.Kind == Water || rect.acted == w.stamp() {
+		return
+	}
+	rect.acted = w.stamp() // Moves and offspring carry the stamp with them.
+	wk.creatures++
+	if rect.Kind == Fish {
+		w.moveFish(wk, x, y)
+	} else if rect.Kind == Shark {
+		w.moveShark(wk, x, y)
+	}
+}
+
+// moveEntity picks the adjacent cell an entity tries to move to, using the
+// worker's random number stream.
+//
+// Under the legacy rules any direction may be picked, and the move fails if
+// that cell is taken. Under the classic rules only water cells are considered,
+// and the entity's own cell is returned if there are none.
+//
+// Directions are those of the configured neighbourhood, and the edges of the
+// grid behave as set by the configured boundary. Terrain that does not admit
+// the entity stops it just like a taken cell.
+func (w *World) moveEntity(wk *worker, x, y int) (newX, newY int) {
+	if w.cfg.Rules == RulesClassic {
+		return w.randomNeighbour(wk, x, y, Water)
+	}
+	newX, newY, _ = w.reachable(x, y, wk.rng.IntN(w.dirs())) // A wall or land stops the move.
+	return newX, newY
+}
+
+// randomNeighbour returns an adjacent cell holding kind that the creature at
+// (x, y) may enter, chosen uniformly at random, or (x, y) itself if there is
+// none.
+func (w *World) randomNeighbour(wk *worker, x, y int, kind Kind) (newX, newY int) {
+	var dirs [maxDirs]int
+	n := 0
+	for dir := range w.dirs() {
+		if nx, ny, ok := w.reachable(x, y, dir); ok && w.at(nx, ny).Kind == kind {
+			dirs[n] = dir
+			n++
+		}
+	}
+	if n == 0 {
+		return x, y
+	}
+	newX, newY, _ = w.reachable(x, y, dirs[wk.rng.IntN(n)])
+	return newX, newY
+}
+
+// moveFish moves a fish to an adjacent water cell.
+//
+// If the fish reaches its breeding threshold, it reproduces in its original cell.
+func (w *World) moveFish(wk *worker, x, y int) {
+	newX, newY := w.moveEntity(wk, x, y)
+	src, dst := w.at(x, y), w.at(newX, newY)
+	if dst.Kind != Water {
+		return // Nowhere to go.
+	}
+	dst.Kind = Fish
+	src.Kind = Water
+	dst.Breed = src.Breed + 1
+	src.Breed = 0
+	dst.acted = src.acted
+	if dst.Breed >= w.cfg.FishBreed {
+		src.Kind = Fish
+		src.Breed = 0
+		dst.Breed = 0
+		wk.born(Fish)
+	}
+}
+
+// moveShark moves a shark to an adjacent cell, eating the fish there if it
+// finds one.
+//
+// Every chronon costs the shark one unit of energy, held in its starvation
+// counter, whether or not it can move. A shark whose energy runs out without
+// eating starves and the cell becomes water. The breeding counter also
+// advances every chronon, and once it reaches the breeding threshold the
+// shark leaves a newborn behind the next time it moves.
+func (w *World) moveShark(wk *worker, x, y int) {
+	src := w.at(x, y)
+	src.Starve--
+	src.Breed++
+
+	newX, newY := w.checkAdjacent(wk, x, y)
+	if newX != x || newY != y {
+		w.eatFish(newX, newY, *src)
+		wk.events.Eaten++
+	} else if src.Starve <= 0 {
+		*src = Cell{acted: src.acted} // Shark starves and the cell becomes water.
+		wk.events.Starved++
+		return
+	} else {
+		newX, newY = w.moveEntity(wk, x, y)
+		dst := w.at(newX, newY)
+		if dst.Kind != Water {
+			return // Nowhere to go.
+		}
+		*dst = *src
+	}
+
+	dst := w.at(newX, newY)
+	*src = Cell{acted: src.acted}
+	if dst.Breed >= w.cfg.SharkBreed {
+		dst.Breed = 0
+		*src = w.newborn(Shark, dst.acted)
+		wk.born(Shark)
+	}
+}
+
+// newborn returns a cell holding a newly born creature of the given kind,
+// stamped as having acted in the chronon it was born.
+func (w *World) newborn(kind Kind, acted int) Cell {
+	c := Cell{Kind: kind, acted: acted}
+	if kind == Shark {
+		c.Starve = w.cfg.SharkStarve
+	}
+	return c
+}
+
+// checkAdjacent checks for fish in the adjacent cells.
+//
+// If a fish is found, it returns the coordinates of the fish. Otherwise, it
+// returns the current cell's coordinates. Fish sheltering on a reef cannot be
+// reached. Under the legacy rules fish to the east are preferred, then west,
+// south and north, followed by any diagonal neighbours; under the classic
+// rules one of the fish is chosen at random.
+func (w *World) checkAdjacent(wk *worker, x, y int) (newx, newy int) {
+	if w.cfg.Rules == RulesClassic {
+		return w.randomNeighbour(wk, x, y, Fish)
+	}
+	for _, dir := range w.topo.legacy {
+		if nx, ny, ok := w.reachable(x, y, dir); ok && w.at(nx, ny).Kind == Fish {
+			return nx, ny
+		}
+	}
+	return x, y
+}
+
+// eatFish allows a shark to eat a fish at a specified cell.
+//
+// The fish is replaced by the shark, whose energy goes up as described by
+// feed. It reports whether there was a fish to eat.
+func (w *World) eatFish(x, y int, shark Cell) bool {
+	rect := w.at(x, y)
+	if rect.Kind != Fish {
+		return false
+	}
+	*rect = shark
+	rect.Starve = w.feed(shark.Starve)
+	return true
+}
+
+// feed returns the energy of a shark that had the given energy left after
+// eating one fish. Each fish is worth FishEnergy, capped at SharkStarve; a
+// FishEnergy of zero refills the shark completely.
+func (w *World) feed(energy int) int {
+	if w.cfg.FishEnergy == 0 {
+		return w.cfg.SharkStarve
+	}
+	return min(energy+w.cfg.FishEnergy, w.cfg.SharkStarve)
+}
@@ -0,0 +1,359 @@
+package wator
+
+import (
+	"slices"
+	"testing"
+)
+
+// find returns the position of the only creature in w.
+func find(t *testing.T, w *World) (x, y int) {
+	t.Helper()
+	found := 0
+	for i := 0; i < w.Width(); i++ {
+		for k := 0; k < w.Height(); k++ {
+			if w.Cell(i, k).Kind != Water {
+				x, y = i, k
+				found++
+			}
+		}
+	}
+	if found != 1 {
+		t.Fatalf("found %d creatures, want 1", found)
+	}
+	return x, y
+}
+
+// adjacent reports whether (x1, y1) is (x0, y0) itself or one of its
+// neighbours in w.
+func adjacent(w *World, x0, y0, x1, y1 int) bool {
+	return x0 == x1 && y0 == y1 || w.directionOf(x0, y0, x1, y1) != stay
+}
+
+func TestCreatureActsOncePerChronon(t *testing.T) {
+	tests := []struct {
+		name string
+		kind Kind
+	}{
+		{"fish", Fish},
+		{"shark", Shark},
+	}
+	for _, mode := range Modes {
+		for _, rules := range RuleSets {
+			for _, hood := range Neighbourhoods {
+				for _, tt := range tests {
+					t.Run(string(mode)+"/"+string(rules)+"/"+string(hood)+"/"+tt.name, func(t *testing.T) {
+						cfg := DefaultConfig()
+						cfg.Width, cfg.Height = 12, 12
+						cfg.NumFish, cfg.NumShark = 0, 0
+						cfg.FishBreed, cfg.SharkBreed, cfg.SharkStarve = 1000, 1000, 1000
+						cfg.Mode, cfg.Rules, cfg.Neighbourhood = mode, rules, hood
+						if tt.kind == Fish {
+							cfg.NumFish = 1
+						} else {
+							cfg.NumShark = 1
+						}
+
+						for seed := uint64(1); seed <= 20; seed++ {
+							cfg.Seed = seed
+							w, err := New(cfg)
+							if err != nil {
+								t.Fatal(err)
+							}
+							x, y := find(t, w)
+							for range 50 {
+								w.Step()
+								nx, ny := find(t, w)
+								if !adjacent(w, x, y, nx, ny) {
+									t.Fatalf("seed %d, chronon %d: %s moved from (%d, %d) to (%d, %d), more than one cell in one step",
+										seed, w.Chronon(), tt.name, x, y, nx, ny)
+								}
+								x, y = nx, ny
+							}
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+// emptyWorld returns a 5x5 world of water using the classic rules, so a
+// creature with a single free neighbour always moves there.
+func emptyWorld(t *testing.T) *World {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = 5, 5
+	cfg.NumFish, cfg.NumShark = 0, 0
+	cfg.SharkBreed, cfg.SharkStarve = 4, 3
+	cfg.Rules = RulesClassic
+	cfg.Seed = 1
+	w, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// boxIn fills the neighbours of (x, y) with kind, except for the
+// directions listed in open, which are left as water.
+func boxIn(w *World, x, y int, kind Kind, open ...int) {
+	for dir := range w.dirs() {
+		if !slices.Contains(open, dir) {
+			nx, ny, _ := w.neighbour(x, y, dir)
+			*w.at(nx, ny) = Cell{Kind: kind, Starve: 100}
+		}
+	}
+}
+
+func TestEatFish(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     Kind
+		fishEnergy int
+		energy     int
+		wantAte    bool
+		wantStarve int
+	}{
+		{"full refill", Fish, 0, 1, true, 3},
+		{"partial gain", Fish, 1, 1, true, 2},
+		{"gain is capped", Fish, 2, 2, true, 3},
+		{"no fish on water", Water, 0, 1, false, 0},
+		{"no fish on shark", Shark, 0, 1, false, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := emptyWorld(t)
+			w.cfg.FishEnergy = tt.fishEnergy
+			*w.at(1, 1) = Cell{Kind: tt.target}
+			if tt.target == Shark {
+				w.at(1, 1).Starve = 100
+			}
+
+			ate := w.eatFish(1, 1, Cell{Kind: Shark, Starve: tt.energy, Breed: 2})
+			if ate != tt.wantAte {
+				t.Fatalf("eatFish reported %v, want %v", ate, tt.wantAte)
+			}
+			got := w.Cell(1, 1)
+			if tt.wantAte && (got.Kind != Shark || got.Breed != 2) {
+				t.Errorf("cell after eating = %+v, want the shark with Breed 2", got)
+			}
+			if got.Starve != tt.wantStarve {
+				t.Errorf("Starve = %d, want %d", got.Starve, tt.wantStarve)
+			}
+		})
+	}
+}
+
+func TestMoveSharkEatsAdjacentFish(t *testing.T) {
+	w := emptyWorld(t)
+	*w.at(2, 2) = Cell{Kind: Shark, Starve: 1}
+	*w.at(3, 2) = Cell{Kind: Fish, Breed: 3}
+
+	w.moveShark(&w.workers[0], 2, 2)
+
+	if got := w.Cell(2, 2); got.Kind != Water || got.Starve != 0 || got.Breed != 0 {
+		t.Errorf("old cell = %+v, want plain water", got)
+	}
+	if got := w.Cell(3, 2); got.Kind != Shark || got.Starve != w.cfg.SharkStarve || got.Breed != 1 {
+		t.Errorf("fish cell = %+v, want a fed shark with Starve %d and Breed 1", got, w.cfg.SharkStarve)
+	}
+}
+
+func TestMoveSharkStarvesWhenBoxedIn(t *testing.T) {
+	w := emptyWorld(t)
+	*w.at(2, 2) = Cell{Kind: Shark, Starve: w.cfg.SharkStarve}
+	boxIn(w, 2, 2, Shark)
+
+	for chronon := 1; chronon < w.cfg.SharkStarve; chronon++ {
+		w.moveShark(&w.workers[0], 2, 2)
+		got := w.Cell(2, 2)
+		if got.Kind != Shark || got.Starve != w.cfg.SharkStarve-chronon {
+			t.Fatalf("after %d chronons cell = %+v, want a shark with Starve %d",
+				chronon, got, w.cfg.SharkStarve-chronon)
+		}
+	}
+	w.moveShark(&w.workers[0], 2, 2)
+	if got := w.Cell(2, 2); got != (Cell{}) {
+		t.Errorf("after %d chronons cell = %+v, want plain water", w.cfg.SharkStarve, got)
+	}
+}
+
+func TestMoveSharkStarvesInOpenWater(t *testing.T) {
+	w := emptyWorld(t)
+	*w.at(2, 2) = Cell{Kind: Shark, Starve: 1}
+
+	w.moveShark(&w.workers[0], 2, 2)
+
+	if fish, sharks := w.Fish(), w.Sharks(); fish != 0 || sharks != 0 {
+		t.Errorf("found %d fish and %d sharks, want the shark to have starved", fish, sharks)
+	}
+}
+
+func TestMoveSharkBreeds(t *testing.T) {
+	tests := []struct {
+		name  string
+		breed int
+		box   bool // Whether the shark is boxed in for its first chronon.
+	}{
+		{"reaches threshold while moving", 3, false},
+		{"breeds on next move after being boxed in", 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := emptyWorld(t)
+			*w.at(2, 2) = Cell{Kind: Shark, Starve: 3, Breed: tt.breed}
+			boxIn(w, 2, 2, Shark, east)
+			if tt.box {
+				*w.at(3, 2) = Cell{Kind: Shark, Starve: 100}
+				w.moveShark(&w.workers[0], 2, 2)
+				if got := w.Cell(2, 2); got.Breed != tt.breed+1 {
+					t.Fatalf("boxed-in shark has Breed %d, want %d", got.Breed, tt.breed+1)
+				}
+				*w.at(3, 2) = Cell{}
+			}
+
+			w.moveShark(&w.workers[0], 2, 2)
+
+			parent, child := w.Cell(3, 2), w.Cell(2, 2)
+			if parent.Kind != Shark || parent.Breed != 0 {
+				t.Errorf("parent = %+v, want a shark with Breed 0", parent)
+			}
+			if child.Kind != Shark || child.Breed != 0 || child.Starve != w.cfg.SharkStarve {
+				t.Errorf("newborn = %+v, want a shark with Breed 0 and Starve %d", child, w.cfg.SharkStarve)
+			}
+		})
+	}
+}
+
+func TestMoveEntityWrapsAround(t *testing.T) {
+	tests := []struct {
+		name         string
+		x, y         int
+		open         int // The only free neighbour.
+		wantX, wantY int
+	}{
+		{"west edge", 0, 2, west, 4, 2},
+		{"east edge", 4, 2, east, 0, 2},
+		{"north edge", 2, 0, north, 2, 4},
+		{"south edge", 2, 4, south, 2, 0},
+		{"corner", 0, 0, north, 0, 4},
+		{"inside", 2, 2, east, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := emptyWorld(t)
+			*w.at(tt.x, tt.y) = Cell{Kind: Fish}
+			boxIn(w, tt.x, tt.y, Fish, tt.open)
+
+			x, y := w.moveEntity(&w.workers[0], tt.x, tt.y)
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("moveEntity(%d, %d) = (%d, %d), want (%d, %d)", tt.x, tt.y, x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestCheckAdjacent(t *testing.T) {
+	tests := []struct {
+		name         string
+		rules        Rules
+		fish         []int // Directions of the neighbouring fish.
+		wantX, wantY int
+	}{
+		{"legacy prefers east", RulesLegacy, []int{north, east}, 3, 2},
+		{"legacy prefers west to south", RulesLegacy, []int{south, west}, 1, 2},
+		{"legacy prefers south to north", RulesLegacy, []int{north, south}, 2, 3},
+		{"legacy finds north last", RulesLegacy, []int{north}, 2, 1},
+		{"legacy without fish", RulesLegacy, nil, 2, 2},
+		{"classic single fish", RulesClassic, []int{west}, 1, 2},
+		{"classic without fish", RulesClassic, nil, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := emptyWorld(t)
+			w.cfg.Rules = tt.rules
+			*w.at(2, 2) = Cell{Kind: Shark, Starve: 3}
+			for _, dir := range tt.fish {
+				nx, ny, _ := w.neighbour(2, 2, dir)
+				*w.at(nx, ny) = Cell{Kind: Fish}
+			}
+
+			x, y := w.checkAdjacent(&w.workers[0], 2, 2)
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("checkAdjacent = (%d, %d), want (%d, %d)", x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestMoveFish(t *testing.T) {
+	tests := []struct {
+		name      string
+		breed     int
+		boxed     bool
+		wantBreed int  // Breeding counter of the fish afterwards.
+		wantChild bool // Whether a newborn is left at the old cell.
+	}{
+		{"ages when moving", 1, false, 2, false},
+		{"breeds at threshold", 4, false, 0, true},
+		{"boxed in keeps counter", 4, true, 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := emptyWorld(t)
+			*w.at(2, 2) = Cell{Kind: Fish, Breed: tt.breed}
+			boxIn(w, 2, 2, Shark, east)
+			if tt.boxed {
+				*w.at(3, 2) = Cell{Kind: Shark, Starve: 100}
+			}
+
+			w.moveFish(&w.workers[0], 2, 2)
+
+			fish := w.Cell(3, 2)
+			if tt.boxed {
+				fish = w.Cell(2, 2)
+			}
+			if fish.Kind != Fish || fish.Breed != tt.wantBreed {
+				t.Errorf("fish = %+v, want a fish with Breed %d", fish, tt.wantBreed)
+			}
+			if !tt.boxed {
+				child := w.Cell(2, 2)
+				if gotChild := child.Kind == Fish; gotChild != tt.wantChild || child.Breed != 0 {
+					t.Errorf("old cell = %+v, want newborn %v", child, tt.wantChild)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceEntities(t *testing.T) {
+	tests := []struct {
+		name         string
+		fish, sharks int
+	}{
+		{"empty", 0, 0},
+		{"sparse", 10, 3},
+		{"only fish", 25, 0},
+		{"full grid", 20, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Width, cfg.Height = 5, 5
+			cfg.NumFish, cfg.NumShark = tt.fish, tt.sharks
+			cfg.Seed = 1
+			w, err := New(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w.Fish() != tt.fish || w.Sharks() != tt.sharks {
+				t.Errorf("placed %d fish and %d sharks, want %d and %d", w.Fish(), w.Sharks(), tt.fish, tt.sharks)
+			}
+			for _, c := range w.cells {
+				if c.Breed != 0 || (c.Kind == Shark) != (c.Starve == cfg.SharkStarve) {
+					t.Fatalf("placed cell %+v, want fresh counters", c)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package wator
+
+// Scheduling.
+//
+// The static schedule gives every worker one large region of the grid, or a
+// fixed share of the checkerboard tiles, whatever lives there. A region
+// crowded with fish then takes far longer to update than an empty one, and
+// the other workers sit idle at the barrier until it is done.
+//
+// The dynamic schedule instead cuts the grid into many small chunks and
+// keeps a shared atomic counter of the next chunk to hand out. Every worker
+// claims a chunk, updates it and comes back for another until none are left,
+// so workers that land on quiet parts of the grid simply take more chunks.
+// Which worker updates which chunk then varies from run to run, and with it
+// the random numbers each creature draws, so a dynamic run is reproducible
+// only with one thread or with Config.Deterministic.
+
+// Schedule selects how the regions of a pass are shared between workers.
+type Schedule string
+
+// Schedules.
+const (
+	ScheduleStatic  Schedule = "static"  // One large region per worker, dealt out in advance.
+	ScheduleDynamic Schedule = "dynamic" // Many small chunks, claimed by whichever worker is free.
+)
+
+// Schedules lists every schedule.
+var Schedules = []Schedule{ScheduleStatic, ScheduleDynamic}
+
+const (
+	chunkRows      = 4 // Height, in rows, of each chunk of the dynamic schedule.
+	tilesPerWorker = 4 // Minimum checkerboard tiles of each colour per worker in the dynamic schedule.
+)
+
+// rowChunks splits a width x height grid into bands of chunkRows rows, the
+// last of which may be shorter.
+func rowChunks(width, height int) []region {
+	chunks := make([]region, 0, (height+chunkRows-1)/chunkRows)
+	for y := 0; y < height; y += chunkRows {
+		chunks = append(chunks, region{x0: 0, x1: width, y0: y, y1: min(y+chunkRows, height)})
+	}
+	return chunks
+}
@@ -0,0 +1,84 @@
+package wator
+
+import "testing"
+
+func TestDynamicScheduleSharesLoad(t *testing.T) {
+	for _, mode := range Modes {
+		if mode == ModePartitioned {
+			continue // Racing threads would trip the race detector.
+		}
+		t.Run(string(mode), func(t *testing.T) {
+			w := newTestWorld(t, func(cfg *Config) {
+				cfg.Width, cfg.Height = 60, 40
+				cfg.NumFish, cfg.NumShark = 600, 60
+				cfg.Mode, cfg.Threads, cfg.Schedule = mode, 3, ScheduleDynamic
+			})
+
+			for range 5 {
+				population := w.Fish() + w.Sharks()
+				w.Step()
+
+				regions := len(w.regions)
+				switch mode {
+				case ModeBuffered:
+					regions *= 2 // Planned, then resolved.
+				case ModeCheckerboard:
+					regions = 0
+					for _, tiles := range w.phases {
+						regions += len(tiles)
+					}
+				}
+				tm := w.Timing()
+				chunks, creatures := 0, 0
+				for i := range tm.Chunks {
+					chunks += tm.Chunks[i]
+					creatures += tm.Creatures[i]
+				}
+				if chunks != regions {
+					t.Errorf("workers updated %d chunks, want %d", chunks, regions)
+				}
+				// In place, a fish eaten before its turn is never updated.
+				least := population - w.Stats().Eaten
+				if mode == ModeBuffered {
+					least = population
+				}
+				if creatures < least || creatures > population {
+					t.Errorf("workers updated %d creatures, want %d to %d of the %d alive at the start of the chronon",
+						creatures, least, population, population)
+				}
+			}
+		})
+	}
+}
+
+func TestDynamicScheduleIsDeterministic(t *testing.T) {
+	var worlds []*World
+	for _, schedule := range Schedules {
+		w := newTestWorld(t, func(cfg *Config) {
+			cfg.Threads, cfg.Seed, cfg.Deterministic, cfg.Schedule = 4, 7, true, schedule
+		})
+		for range 20 {
+			w.Step()
+		}
+		worlds = append(worlds, w)
+	}
+	if i := sameCells(worlds[0], worlds[1]); i >= 0 {
+		t.Fatalf("cell %d is %+v under the static schedule but %+v under the dynamic one", i, worlds[0].cells[i], worlds[1].cells[i])
+	}
+}
+
+func TestRowChunks(t *testing.T) {
+	for _, height := range []int{1, 3, 4, 10, 150} {
+		chunks := rowChunks(7, height)
+		y := 0
+		for _, c := range chunks {
+			if c.x0 != 0 || c.x1 != 7 || c.y0 != y || c.y1 <= c.y0 || c.y1-c.y0 > chunkRows {
+				t.Fatalf("height %d: chunk %+v does not follow row %d", height, c, y)
+			}
+			y = c.y1
+		}
+		if y != height {
+			t.Errorf("height %d: chunks cover %d rows", height, y)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package wator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+)
+
+// Snapshots.
+//
+// A snapshot records everything needed to carry on a run exactly where it
+// left off: the parameters, the chronon, every cell with its counters, the
+// terrain and the state of every worker's random number generator. Resuming
+// a snapshot therefore gives the same history as the original run would
+// have had from that point on, wherever that is guaranteed for a seed (see
+// New).
+//
+// Snapshots are stored as JSON with a version number. Files written by an
+// older version of the format are still read, and files from a newer version
+// are rejected. Each cell's kind, counters and terrain are stored as arrays
+// of numbers indexed like the grid, using the values of the Kind and Terrain
+// constants; only the generator states, which are opaque, are base64.
+
+// snapshotVersion is the version of the snapshot format written by Save.
+const snapshotVersion = 1
+
+// snapshot is the JSON form of a saved world.
+type snapshot struct {
+	Version int      `json:"version"`
+	Config  Config   `json:"config"`
+	Chronon int      `json:"chronon"`
+	Kinds   []int    `json:"kinds"`             // Contents of each cell, indexed like the grid.
+	Starve  []int    `json:"starve"`            // Starvation counter of each cell.
+	Breed   []int    `json:"breed"`             // Breeding counter of each cell.
+	Terrain []int    `json:"terrain,omitempty"` // Terrain of each cell, if not all open sea.
+	Workers [][]byte `json:"workers"`           // Binary state of each worker's generator.
+}
+
+// Save writes a snapshot of the world to path. The file is written in full
+// before it replaces any existing file, so an interrupted save never leaves
+// a truncated snapshot behind.
+func (w *World) Save(path string) error {
+	s := snapshot{
+		Version: snapshotVersion,
+		Config:  w.cfg,
+		Chronon: w.chronon,
+		Kinds:   make([]int, len(w.cells)),
+		Starve:  make([]int, len(w.cells)),
+		Breed:   make([]int, len(w.cells)),
+		Workers: make([][]byte, len(w.workers)),
+	}
+	for i, c := range w.cells {
+		s.Kinds[i], s.Starve[i], s.Breed[i] = int(c.Kind), c.Starve, c.Breed
+	}
+	if w.terrain != nil {
+		s.Terrain = make([]int, len(w.terrain))
+		for i, t := range w.terrain {
+			s.Terrain[i] = int(t)
+		}
+	}
+	for t, wk := range w.workers {
+		state, err := wk.pcg.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		s.Workers[t] = state
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed.
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads a world from a snapshot written by Save. The world keeps the
+// parameters it was saved with, including its number of threads. A snapshot
+// whose cells break any of the invariants verified by Check is rejected.
+func Load(path string) (*World, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	w, err := s.world()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return w, nil
+}
+
+// world rebuilds the world recorded in s.
+func (s *snapshot) world() (*World, error) {
+	if s.Version < 1 || s.Version > snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (want 1 to %d)", s.Version, snapshotVersion)
+	}
+	if s.Config.Schedule == "" {
+		s.Config.Schedule = ScheduleStatic // Saved before there was a choice of schedule.
+	}
+	if err := s.Config.Validate(); err != nil {
+		return nil, err
+	}
+	size := s.Config.Width * s.Config.Height
+	switch {
+	case s.Config.Seed == 0:
+		return nil, fmt.Errorf("snapshot has no seed")
+	case s.Chronon < 0:
+		return nil, fmt.Errorf("negative chronon %d", s.Chronon)
+	case len(s.Kinds) != size || len(s.Starve) != size || len(s.Breed) != size:
+		return nil, fmt.Errorf("snapshot does not hold %d cells", size)
+	case s.Terrain != nil && len(s.Terrain) != size:
+		return nil, fmt.Errorf("snapshot does not hold terrain for %d cells", size)
+	case len(s.Workers) != s.Config.Threads:
+		return nil, fmt.Errorf("snapshot holds %d random number generators, want %d", len(s.Workers), s.Config.Threads)
+	}
+
+	w := newWorld(s.Config)
+	w.chronon = s.Chronon
+	if s.Terrain != nil {
+		w.terrain = make([]Terrain, size)
+	}
+	for i := range w.cells {
+		x, y := i/s.Config.Height, i%s.Config.Height
+		kind, terrain := Kind(s.Kinds[i]), Sea
+		if s.Terrain != nil {
+			terrain = Terrain(s.Terrain[i])
+		}
+		switch {
+		case s.Kinds[i] < 0 || s.Kinds[i] > int(Shark):
+			return nil, fmt.Errorf("cell (%d, %d) holds unknown kind %d", x, y, s.Kinds[i])
+		case s.Terrain != nil && (s.Terrain[i] < 0 || s.Terrain[i] > int(Reef)):
+			return nil, fmt.Errorf("cell (%d, %d) has unknown terrain %d", x, y, s.Terrain[i])
+		}
+		w.cells[i] = Cell{Kind: kind, Starve: s.Starve[i], Breed: s.Breed[i]}
+		if w.terrain != nil {
+			w.terrain[i] = terrain
+		}
+	}
+	for t := range w.workers {
+		pcg := new(rand.PCG)
+		if err := pcg.UnmarshalBinary(s.Workers[t]); err != nil {
+			return nil, fmt.Errorf("random number generator %d: %w", t, err)
+		}
+		w.workers[t] = worker{pcg: pcg, rng: rand.New(pcg)}
+	}
+	w.census()
+	if err := w.Check(); err != nil { // Counters out of range, creatures on land and so on.
+		return nil, err
+	}
+	return w, nil
+}
@@ -0,0 +1,94 @@
+package wator
+
+import (
+	"encoding/base64"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoadResumesExactly(t *testing.T) {
+	for _, mode := range []Mode{ModeBuffered, ModeCheckerboard} {
+		t.Run(string(mode), func(t *testing.T) {
+			w := newTestWorld(t, func(cfg *Config) {
+				cfg.Threads, cfg.Mode, cfg.Seed = 4, mode, 7
+			})
+			for range 25 {
+				w.Step()
+			}
+
+			path := filepath.Join(t.TempDir(), "run.json")
+			if err := w.Save(path); err != nil {
+				t.Fatal(err)
+			}
+			if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"kinds":[`) {
+				t.Fatalf("snapshot does not store the kinds as a JSON array of numbers (err %v)", err)
+			}
+			resumed, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resumed.Chronon() != w.Chronon() || resumed.Config() != w.Config() {
+				t.Fatalf("resumed chronon %d with %+v, want chronon %d with %+v",
+					resumed.Chronon(), resumed.Config(), w.Chronon(), w.Config())
+			}
+			for range 25 {
+				w.Step()
+				resumed.Step()
+			}
+			if i := sameCells(w, resumed); i >= 0 {
+				t.Errorf("resumed world differs from the original at cell %d after 25 more chronons", i)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsBadSnapshots(t *testing.T) {
+	// Pieces of a valid 2x2 snapshot holding a fish at (1, 0) and a shark at
+	// (1, 1), from which each case builds a broken one.
+	state, err := rand.NewPCG(1, 1).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := `{"version": 1, "chronon": 0, "config": {"width": 2, "height": 2, "fish_breed": 1, "shark_breed": 1,
+		"shark_starve": 1, "threads": 1, "mode": "buffered", "rules": "legacy", "neighbourhood": "vonneumann",
+		"boundary": "toroidal", "schedule": "static", "seed": 1}`
+	cells := `, "kinds": [0, 0, 1, 2], "starve": [0, 0, 0, 1], "breed": [0, 0, 0, 0]`
+	workers := `, "workers": ["` + base64.StdEncoding.EncodeToString(state) + `"]`
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"valid", header + cells + workers + `}`, false},
+		{"valid with terrain", header + cells + `, "terrain": [1, 0, 2, 0]` + workers + `}`, false},
+		{"not json", "wa-tor", true},
+		{"future version", `{"version": 99}`, true},
+		{"missing cells", header + `}`, true},
+		{"unknown kind", header + `, "kinds": [0, 3, 0, 0], "starve": [0, 0, 0, 0], "breed": [0, 0, 0, 0]` + workers + `}`, true},
+		{"unknown terrain", header + cells + `, "terrain": [9, 0, 0, 0]` + workers + `}`, true},
+		{"negative starve", header + `, "kinds": [0, 0, 1, 2], "starve": [-5, 0, 0, 1], "breed": [0, 0, 0, 0]` + workers + `}`, true},
+		{"negative breed", header + `, "kinds": [0, 0, 1, 2], "starve": [0, 0, 0, 1], "breed": [0, 0, -1, 0]` + workers + `}`, true},
+		{"shark on reef", header + cells + `, "terrain": [0, 0, 0, 2]` + workers + `}`, true},
+		{"fish on land", header + cells + `, "terrain": [0, 0, 1, 0]` + workers + `}`, true},
+		{"water with breed counter", header + `, "kinds": [0, 0, 1, 2], "starve": [0, 0, 0, 1], "breed": [7, 0, 0, 0]` + workers + `}`, true},
+		{"water with starve counter", header + `, "kinds": [0, 0, 1, 2], "starve": [0, 3, 0, 1], "breed": [0, 0, 0, 0]` + workers + `}`, true},
+		{"fish with starve counter", header + `, "kinds": [0, 0, 1, 2], "starve": [0, 0, 1, 1], "breed": [0, 0, 0, 0]` + workers + `}`, true},
+		{"shark without energy", header + `, "kinds": [0, 0, 1, 2], "starve": [0, 0, 0, 0], "breed": [0, 0, 0, 0]` + workers + `}`, true},
+		{"shark above shark_starve", header + `, "kinds": [0, 0, 1, 2], "starve": [0, 0, 0, 2], "breed": [0, 0, 0, 0]` + workers + `}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "snapshot.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
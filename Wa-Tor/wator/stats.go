@@ -0,0 +1,57 @@
+package wator
+
+// Stats describes the population of a world and what happened to it during
+// the last chronon.
+//
+// The counts are kept up to date from the births and deaths recorded while
+// the grid is updated, rather than by scanning the grid after each chronon.
+// Every goroutine records events in counters of its own, which are added up
+// once all of them have finished the chronon.
+type Stats struct {
+	Fish       int // Fish alive at the end of the chronon.
+	Sharks     int // Sharks alive at the end of the chronon.
+	FishBorn   int // Fish born during the chronon.
+	SharksBorn int // Sharks born during the chronon.
+	Starved    int // Sharks that starved during the chronon.
+	Eaten      int // Fish eaten by sharks during the chronon.
+}
+
+// Stats returns the population after the last chronon and the births and
+// deaths during it. Before the first chronon only the population is set.
+//
+// In the partitioned mode with more than one thread, racing updates can
+// create or lose creatures without a recorded event, so the counts can drift
+// from those reported by Fish and Sharks.
+func (w *World) Stats() Stats { return w.stats }
+
+// census counts the creatures on the grid to start the running totals.
+func (w *World) census() {
+	w.stats = Stats{Fish: w.count(Fish), Sharks: w.count(Shark)}
+}
+
+// born records the birth of a creature of the given kind.
+func (wk *worker) born(kind Kind) {
+	if kind == Shark {
+		wk.events.SharksBorn++
+	} else {
+		wk.events.FishBorn++
+	}
+}
+
+// tally adds up the events recorded by every worker during the chronon just
+// finished, updates the running population totals and clears the workers'
+// counters for the next chronon.
+func (w *World) tally() {
+	st := Stats{Fish: w.stats.Fish, Sharks: w.stats.Sharks}
+	for t := range w.workers {
+		ev := &w.workers[t].events
+		st.FishBorn += ev.FishBorn
+		st.SharksBorn += ev.SharksBorn
+		st.Starved += ev.Starved
+		st.Eaten += ev.Eaten
+		*ev = Stats{}
+	}
+	st.Fish += st.FishBorn - st.Eaten
+	st.Sharks += st.SharksBorn - st.Starved
+	w.stats = st
+}
@@ -0,0 +1,32 @@
+package wator
+
+import "testing"
+
+func TestStatsTrackPopulation(t *testing.T) {
+	for _, mode := range Modes {
+		t.Run(string(mode), func(t *testing.T) {
+			w := newTestWorld(t, func(cfg *Config) {
+				cfg.NumShark = 40
+				cfg.Mode, cfg.Threads, cfg.Seed = mode, 4, 3
+				if mode == ModePartitioned {
+					cfg.Threads = 1 // Racing threads lose creatures without a trace.
+				}
+			})
+
+			var births, deaths int
+			for range 100 {
+				w.Step()
+				st := w.Stats()
+				if st.Fish != w.Fish() || st.Sharks != w.Sharks() {
+					t.Fatalf("chronon %d: stats count %d fish and %d sharks, grid holds %d and %d",
+						w.Chronon(), st.Fish, st.Sharks, w.Fish(), w.Sharks())
+				}
+				births += st.FishBorn + st.SharksBorn
+				deaths += st.Starved + st.Eaten
+			}
+			if births == 0 || deaths == 0 {
+				t.Errorf("recorded %d births and %d deaths in 100 chronons, want some of each", births, deaths)
+			}
+		})
+	}
+}
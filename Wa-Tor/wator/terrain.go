@@ -0,0 +1,83 @@
+package wator
+
+import "fmt"
+
+// Terrain.
+//
+// Beneath the creatures lies a static layer of terrain, loaded from a map
+// file when the world is created. Open sea is the default. Land cannot be entered
+// at all, and reefs can only be entered by fish, so a fish on a reef is safe
+// from sharks. Terrain only restricts where a creature may move; it never
+// changes, and everything else about a cell works as in open sea.
+
+// Terrain is the static ground beneath a cell.
+type Terrain uint8
+
+// Terrain types. The zero value is open sea.
+const (
+	Sea  Terrain = iota // Open to every creature.
+	Land                // Impassable; nothing lives here.
+	Reef                // Open to fish only, sheltering them from sharks.
+)
+
+// String returns the name of the terrain.
+func (t Terrain) String() string {
+	switch t {
+	case Sea:
+		return "sea"
+	case Land:
+		return "land"
+	case Reef:
+		return "reef"
+	}
+	return fmt.Sprintf("Terrain(%d)", uint8(t))
+}
+
+// admits reports whether a creature of the given kind may be in terrain t.
+func (t Terrain) admits(kind Kind) bool {
+	switch t {
+	case Land:
+		return false
+	case Reef:
+		return kind == Fish
+	}
+	return true
+}
+
+// Terrain returns the terrain at (x, y). It panics if (x, y) lies outside
+// the grid.
+func (w *World) Terrain(x, y int) Terrain {
+	w.checkBounds(x, y)
+	return w.terrainAt(x, y)
+}
+
+// terrainAt returns the terrain at (x, y), which must lie on the grid.
+func (w *World) terrainAt(x, y int) Terrain {
+	if w.terrain == nil {
+		return Sea
+	}
+	return w.terrain[w.index(x, y)]
+}
+
+// reachable returns the neighbour of (x, y) in direction dir if the creature
+// at (x, y) may enter it. Otherwise it returns (x, y) itself and false.
+func (w *World) reachable(x, y, dir int) (int, int, bool) {
+	nx, ny, ok := w.neighbour(x, y, dir)
+	if !ok || !w.terrainAt(nx, ny).admits(w.at(x, y).Kind) {
+		return x, y, false
+	}
+	return nx, ny, true
+}
+
+// openSea returns the number of cells in which any creature may live.
+func (w *World) openSea() int {
+	n := 0
+	for x := range w.cfg.Width {
+		for y := range w.cfg.Height {
+			if w.terrainAt(x, y) == Sea {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,54 @@
+package wator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// terrainWorld returns a world whose terrain is read from the text map rows,
+// with no creatures and the classic rules.
+func terrainWorld(t *testing.T, rows string, width, height int) *World {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "map.txt")
+	if err := os.WriteFile(path, []byte(rows), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = width, height
+	cfg.NumFish, cfg.NumShark = 0, 0
+	cfg.SharkStarve = 100
+	cfg.Rules = RulesClassic
+	cfg.TerrainMap = path
+	cfg.Seed = 1
+	w, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestTerrainBlocksMoves(t *testing.T) {
+	// A 3x3 pond of sea surrounded by land, with a reef in its middle.
+	w := terrainWorld(t, "#####\n#...#\n#.~.#\n#...#\n#####\n", 5, 5)
+	*w.at(1, 1) = Cell{Kind: Shark, Starve: 100}
+	*w.at(3, 3) = Cell{Kind: Fish}
+	*w.at(2, 2) = Cell{Kind: Fish}
+
+	for range 200 {
+		w.Step()
+		if got := w.Cell(2, 2); got.Kind != Fish {
+			if got.Kind == Shark {
+				t.Fatalf("chronon %d: a shark entered the reef", w.Chronon())
+			}
+			*w.at(2, 2) = Cell{Kind: Fish} // The fish swam off; put one back.
+		}
+		for x := range 5 {
+			for y := range 5 {
+				if w.Terrain(x, y) == Land && w.Cell(x, y).Kind != Water {
+					t.Fatalf("chronon %d: %s on land at (%d, %d)", w.Chronon(), w.Cell(x, y).Kind, x, y)
+				}
+			}
+		}
+	}
+}
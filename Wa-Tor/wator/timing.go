@@ -0,0 +1,68 @@
+package wator
+
+import (
+	"slices"
+	"time"
+)
+
+// Timing describes how long the last chronon took, measured with the
+// monotonic clock, and how the work was shared between the workers.
+//
+// A chronon is made of one or more passes over the grid, each ending at a
+// barrier where every worker waits for the others. Busy and Wait split each
+// worker's share of the step between updating cells and waiting at those
+// barriers; a worker left without a region in a pass waits for all of it.
+// Chunks and Creatures give each worker's load, which the dynamic schedule
+// evens out: a creature counts once for every pass that moves or plans it.
+type Timing struct {
+	Step time.Duration   // Time taken by the whole step.
+	Busy []time.Duration // Time each worker spent updating cells.
+	Wait []time.Duration // Time each worker spent waiting at barriers for the others.
+
+	Chunks    []int // Regions of the grid each worker updated, over every pass.
+	Creatures []int // Creatures each worker updated, over every pass.
+}
+
+// Timing returns the timing of the last chronon. Before the first chronon
+// every duration and count is zero.
+func (w *World) Timing() Timing {
+	t := w.timing
+	t.Busy, t.Wait = slices.Clone(t.Busy), slices.Clone(t.Wait)
+	t.Chunks, t.Creatures = slices.Clone(t.Chunks), slices.Clone(t.Creatures)
+	return t
+}
+
+// clockOut records that the worker has finished its share of a pass that it
+// began at start.
+func (wk *worker) clockOut(start time.Time) {
+	wk.finished = time.Now()
+	wk.busy += wk.finished.Sub(start)
+}
+
+// clockBarrier charges each worker for the time between finishing its share
+// of a pass and the release of the barrier at end. Workers beyond the first
+// n had nothing to do in the pass, which began at start.
+func (w *World) clockBarrier(start, end time.Time, n int) {
+	for t := range w.workers {
+		wk := &w.workers[t]
+		if t < n {
+			wk.wait += end.Sub(wk.finished)
+		} else {
+			wk.wait += end.Sub(start)
+		}
+	}
+}
+
+// recordTiming stores the timing and load of the chronon just finished,
+// which took step, and clears the workers' clocks and counters for the next
+// one.
+func (w *World) recordTiming(step time.Duration) {
+	w.timing.Step = step
+	for t := range w.workers {
+		wk := &w.workers[t]
+		w.timing.Busy[t], w.timing.Wait[t] = wk.busy, wk.wait
+		w.timing.Chunks[t], w.timing.Creatures[t] = wk.chunks, wk.creatures
+		wk.busy, wk.wait = 0, 0
+		wk.chunks, wk.creatures = 0, 0
+	}
+}
@@ -0,0 +1,36 @@
+package wator
+
+import "testing"
+
+func TestTimingCoversEveryWorker(t *testing.T) {
+	for _, mode := range Modes {
+		t.Run(string(mode), func(t *testing.T) {
+			w := newTestWorld(t, func(cfg *Config) {
+				cfg.Width, cfg.Height = 60, 40
+				cfg.NumFish, cfg.NumShark = 600, 60
+				cfg.Mode, cfg.Threads = mode, 3
+				if mode == ModePartitioned {
+					cfg.Threads = 1 // Racing threads would trip the race detector.
+				}
+			})
+			threads := w.Config().Threads
+			if tm := w.Timing(); tm.Step != 0 || len(tm.Busy) != threads || len(tm.Wait) != threads {
+				t.Fatalf("timing before the first step = %+v, want zeros for %d workers", tm, threads)
+			}
+			w.Step()
+
+			tm := w.Timing()
+			if tm.Step <= 0 {
+				t.Fatalf("step took %v, want a positive duration", tm.Step)
+			}
+			for i := range tm.Busy {
+				if tm.Busy[i] <= 0 || tm.Wait[i] < 0 {
+					t.Errorf("worker %d was busy for %v and waited %v, want positive busy time", i, tm.Busy[i], tm.Wait[i])
+				}
+				if tm.Busy[i]+tm.Wait[i] > tm.Step {
+					t.Errorf("worker %d was busy for %v and waited %v, more than the %v step", i, tm.Busy[i], tm.Wait[i], tm.Step)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+// Package wator implements the Wa-Tor predator-prey simulation.
+//
+// A World is a grid of cells, each holding water, a fish or a shark, whose
+// neighbours are given by a von Neumann, Moore or hexagonal neighbourhood.
+// The grid is a torus by default; its edges can instead be walls, reflect
+// creatures back, or be glued into a Klein bottle.
+// Every call to Step advances the world by one chronon, sharing the work
+// between the number of goroutines and using the update strategy chosen in
+// its Config. The package does no rendering; frontends read the grid back
+// through Cell.
+package wator
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kind is what occupies a cell.
+type Kind uint8
+
+// Cell contents. The zero value is water.
+const (
+	Water Kind = iota
+	Fish
+	Shark
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Water:
+		return "water"
+	case Fish:
+		return "fish"
+	case Shark:
+		return "shark"
+	}
+	return fmt.Sprintf("Kind(%d)", uint8(k))
+}
+
+// Cell is the state of a single grid cell.
+type Cell struct {
+	Kind   Kind // Contents of the cell (fish, shark, or water).
+	Starve int  // Starvation counter for sharks.
+	Breed  int  // Breeding counter for both fish and sharks.
+	acted  int  // Stamp of the last chronon the creature acted in, for in-place updates.
+}
+
+// World is a Wa-Tor simulation.
+type World struct {
+	cfg     Config
+	topo    topology     // Directions of the configured neighbourhood.
+	cells   []Cell       // Current state of the grid, indexed by x*Height+y.
+	terrain []Terrain    // Static terrain beneath the grid, or nil for open sea.
+	next    []Cell       // Grid being written during a buffered step.
+	intents []int8       // Direction each creature wants to move, for a buffered step.
+	regions []region     // Regions of the grid shared between the threads.
+	phases  [][]region   // Tiles of each colour, for a checkerboard step.
+	locks   []sync.Mutex // One mutex per lock tile, for a locked step.
+	workers []worker     // State private to each updating goroutine.
+	pool    *pool        // Long-lived goroutines running the workers, once started.
+	chronon int          // Number of steps taken so far.
+	stats   Stats        // Population and events of the last chronon.
+	timing  Timing       // How long the last chronon took.
+}
+
+// worker holds the state private to one of the goroutines that update the
+// grid, so the goroutines never contend for it.
+type worker struct {
+	pcg    *rand.PCG  // Generator behind rng, reseeded per cell in deterministic runs.
+	rng    *rand.Rand // Random number stream of this worker.
+	events Stats      // Births and deaths seen by this worker in the current chronon.
+
+	busy     time.Duration // Time spent updating cells in the current chronon.
+	wait     time.Duration // Time spent waiting at barriers in the current chronon.
+	finished time.Time     // When the worker last finished its share of a pass.
+
+	chunks    int // Regions updated in the current chronon.
+	creatures int // Creatures updated in the current chronon.
+}
+
+// stream returns random number stream n of the given seed. Streams with
+// different n are independent of one another.
+func stream(seed uint64, n int) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, uint64(n)))
+}
+
+// New creates a world from cfg. If cfg names a world map, the grid starts out
+// exactly as the map shows; otherwise the terrain map, if any, is loaded and
+// the starting fish and sharks are scattered at random over the open sea. It
+// returns an error if cfg is not valid or a map cannot be used.
+//
+// All randomness is derived from cfg.Seed: the starting positions come from
+// stream 0 and worker t draws from stream t+1. A zero seed is replaced by a
+// random one, which Config reports. Two worlds with the same Config then have
+// identical histories in the buffered and checkerboard modes under the static
+// schedule, and in every mode when run with a single thread. With
+// cfg.Deterministic set, the history is also the same for any number of
+// threads.
+func New(cfg Config) (*World, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	for cfg.Seed == 0 {
+		cfg.Seed = rand.Uint64()
+	}
+
+	w := newWorld(cfg)
+	switch {
+	case cfg.WorldMap != "":
+		if err := w.loadMap(cfg.WorldMap, true); err != nil {
+			return nil, err
+		}
+		w.census()
+		return w, nil // The map supplies the starting fish and sharks.
+	case cfg.TerrainMap != "":
+		if err := w.loadMap(cfg.TerrainMap, false); err != nil {
+			return nil, err
+		}
+		if sea := w.openSea(); cfg.NumFish+cfg.NumShark > sea {
+			return nil, fmt.Errorf("%d fish and %d sharks do not fit in the %d cells of open sea in %s",
+				cfg.NumFish, cfg.NumShark, sea, cfg.TerrainMap)
+		}
+	}
+
+	rng := stream(cfg.Seed, 0)
+	w.placeEntities(rng, cfg.NumFish, Fish)   // Place initial fish.
+	w.placeEntities(rng, cfg.NumShark, Shark) // Place initial sharks.
+	w.census()
+	return w, nil
+}
+
+// newWorld returns an empty world of water for a valid cfg with a non-zero
+// seed, with its random number streams seeded.
+func newWorld(cfg Config) *World {
+	size := cfg.Width * cfg.Height
+	w := &World{
+		cfg:     cfg,
+		topo:    topologies[cfg.Neighbourhood],
+		cells:   make([]Cell, size),
+		next:    make([]Cell, size),
+		intents: make([]int8, size),
+		locks:   make([]sync.Mutex, lockTiles(cfg.Width)*lockTiles(cfg.Height)),
+		workers: make([]worker, cfg.Threads),
+		timing: Timing{
+			Busy: make([]time.Duration, cfg.Threads),
+			Wait: make([]time.Duration, cfg.Threads),
+
+			Chunks:    make([]int, cfg.Threads),
+			Creatures: make([]int, cfg.Threads),
+		},
+	}
+	if cfg.Schedule == ScheduleDynamic {
+		w.regions = rowChunks(cfg.Width, cfg.Height)
+		w.phases = checkerboardTiles(tilesPerWorker*cfg.Threads, cfg.Width, cfg.Height)
+	} else {
+		w.regions = partitionGrid(cfg.Threads, cfg.Width, cfg.Height)
+		w.phases = checkerboardTiles(cfg.Threads, cfg.Width, cfg.Height)
+	}
+	for t := range w.workers {
+		pcg := rand.NewPCG(cfg.Seed, uint64(t+1))
+		w.workers[t] = worker{pcg: pcg, rng: rand.New(pcg)}
+	}
+	return w
+}
+
+// Config returns the parameters the world was created with.
+func (w *World) Config() Config { return w.cfg }
+
+// Width returns the width of the grid in cells.
+func (w *World) Width() int { return w.cfg.Width }
+
+// Height returns the height of the grid in cells.
+func (w *World) Height() int { return w.cfg.Height }
+
+// Chronon returns the number of steps taken so far.
+func (w *World) Chronon() int { return w.chronon }
+
+// Cell returns the state of the cell at (x, y). It panics if (x, y) lies
+// outside the grid.
+func (w *World) Cell(x, y int) Cell {
+	w.checkBounds(x, y)
+	return *w.at(x, y)
+}
+
+// checkBounds panics if (x, y) lies outside the grid. Cells are stored in a
+// single slice, so an out-of-range coordinate would otherwise silently wrap
+// to a different cell.
+func (w *World) checkBounds(x, y int) {
+	if x < 0 || x >= w.cfg.Width || y < 0 || y >= w.cfg.Height {
+		panic(fmt.Sprintf("wator: cell (%d, %d) is outside the %dx%d grid", x, y, w.cfg.Width, w.cfg.Height))
+	}
+}
+
+// Fish returns the number of fish in the world, counting them on the grid.
+func (w *World) Fish() int { return w.count(Fish) }
+
+// Sharks returns the number of sharks in the world, counting them on the grid.
+func (w *World) Sharks() int { return w.count(Shark) }
+
+// count returns the number of cells holding kind.
+func (w *World) count(kind Kind) int {
+	n := 0
+	for i := range w.cells {
+		if w.cells[i].Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// stamp identifies the current chronon in Cell.acted. It starts at 1 so that
+// a fresh cell has never acted.
+func (w *World) stamp() int {
+	return w.chronon + 1
+}
+
+// index returns the position of (x, y) in the grid slices.
+func (w *World) index(x, y int) int {
+	return x*w.cfg.Height + y
+}
+
+// at returns a pointer to the cell at (x, y) in the current grid.
+func (w *World) at(x, y int) *Cell {
+	return &w.cells[w.index(x, y)]
+}
+
+// Step advances the world by one chronon using the configured update mode.
+func (w *World) Step() {
+	start := time.Now()
+	switch w.cfg.Mode {
+	case ModeBuffered:
+		w.forEachCell(w.regions, w.planCell)    // Every creature picks a move from the current grid.
+		w.forEachCell(w.regions, w.resolveCell) // Every cell works out its next state.
+		w.cells, w.next = w.next, w.cells
+	case ModeCheckerboard:
+		for _, tiles := range w.phases {
+			w.forEachCell(tiles, w.updateCell) // Returns only once the whole colour is done.
+		}
+	case ModeLocked:
+		w.forEachCell(w.regions, w.lockedUpdateCell)
+	default:
+		w.forEachCell(w.regions, w.updateCell)
+	}
+	w.tally()
+	w.recordTiming(time.Since(start))
+	w.chronon++
+}
+
+// region is a rectangular block of the grid updated by a single goroutine.
+type region struct {
+	x0, x1 int // Column range [x0, x1).
+	y0, y1 int // Row range [y0, y1).
+}
+
+// pass is one sweep of an update function over a set of regions, shared
+// between the workers.
+type pass struct {
+	regions []region
+	fn      func(wk *worker, x, y int)
+	claimed atomic.Int64 // Regions handed out so far, in the dynamic schedule.
+}
+
+// forEachCell calls fn on every cell of the given regions and returns once
+// all of them are done. The work is shared between up to Threads workers,
+// run either by goroutines started for the pass or by the world's pool of
+// long-lived goroutines. The time each worker spends on its regions and
+// waiting for the others to finish is added to its timing, and the regions
+// it visits to its load.
+func (w *World) forEachCell(regions []region, fn func(wk *worker, x, y int)) {
+	p := &pass{regions: regions, fn: fn}
+	start := time.Now()
+	if w.cfg.Pool {
+		if w.pool == nil {
+			w.startPool()
+		}
+		w.pool.run(p)
+	} else {
+		var wg sync.WaitGroup
+		for t := range min(w.cfg.Threads, len(regions)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.work(t, p)
+			}()
+		}
+		wg.Wait() // Wait for every region to finish
+	}
+	w.clockBarrier(start, time.Now(), len(regions))
+}
+
+// work runs worker t's share of pass p. Under the static schedule the
+// regions are shared out round-robin, so each worker always visits the same
+// cells in the same order; under the dynamic schedule each worker claims the
+// next unvisited region whenever it finishes one.
+func (w *World) work(t int, p *pass) {
+	wk := &w.workers[t]
+	defer wk.clockOut(time.Now())
+	dynamic := w.cfg.Schedule == ScheduleDynamic
+	j := t
+	if dynamic {
+		j = p.claim()
+	}
+	for j < len(p.regions) {
+		r := p.regions[j]
+		wk.chunks++
+		for i := r.x0; i < r.x1; i++ {
+			for k := r.y0; k < r.y1; k++ {
+				if w.cfg.Deterministic {
+					w.seedCell(wk, i, k)
+				}
+				p.fn(wk, i, k)
+			}
+		}
+		if dynamic {
+			j = p.claim()
+		} else {
+			j += w.cfg.Threads // Round-robin.
+		}
+	}
+}
+
+// claim returns the index of the next region of the pass that no worker has
+// claimed yet, which is past the last region once all have been.
+func (p *pass) claim() int {
+	return int(p.claimed.Add(1) - 1)
+}
+
+// seedCell reseeds the worker's generator from the seed, the chronon and the
+// position of (x, y). Whichever worker visits a cell then draws the same
+// numbers for it, so the draws do not depend on how the grid is partitioned.
+// The seed is mixed before the chronon is folded in, so that seed s at
+// chronon c+1 does not repeat the draws of seed s+1 at chronon c.
+func (w *World) seedCell(wk *worker, x, y int) {
+	wk.pcg.Seed(mix(mix(w.cfg.Seed)^uint64(w.chronon)), mix(uint64(w.index(x, y))))
+}
+
+// mix is the SplitMix64 finaliser. It spreads consecutive counters over the
+// whole range of seeds so neighbouring cells get unrelated streams.
+func mix(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// partitionGrid splits a width x height grid into n regions of roughly equal
+// size.
+//
+// The regions are laid out as a cols x rows arrangement of tiles, where rows
+// is the largest divisor of n not exceeding its square root, so 4 threads get
+// quadrants and 8 threads get a 4x2 layout. A prime n gives n vertical strips,
+// some of which are empty if n is larger than the grid width.
+func partitionGrid(n, width, height int) []region {
+	rows := 1
+	for d := 1; d*d <= n; d++ {
+		if n%d == 0 {
+			rows = d
+		}
+	}
+	cols := n / rows
+
+	regions := make([]region, 0, n)
+	for c := 0; c < cols; c++ {
+		for r := 0; r < rows; r++ {
+			regions = append(regions, region{
+				x0: c * width / cols, x1: (c + 1) * width / cols,
+				y0: r * height / rows, y1: (r + 1) * height / rows,
+			})
+		}
+	}
+	return regions
+}
@@ -0,0 +1,181 @@
+package wator
+
+import (
+	"fmt"
+	"testing"
+)
+
+// testConfig returns the config of a 40x30 world of 300 fish and 30 sharks
+// with seed 1, after edit, if not nil, has adjusted it.
+func testConfig(edit func(*Config)) Config {
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = 40, 30
+	cfg.NumFish, cfg.NumShark = 300, 30
+	cfg.Seed = 1
+	if edit != nil {
+		edit(&cfg)
+	}
+	return cfg
+}
+
+// newTestWorld returns a new world with the config from testConfig. Any
+// worker pool it starts is closed when the test ends.
+func newTestWorld(tb testing.TB, edit func(*Config)) *World {
+	tb.Helper()
+	w, err := New(testConfig(edit))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(w.Close)
+	return w
+}
+
+// sameCells returns the index of the first cell whose kind or counters
+// differ between a and b, or -1 if they hold the same creatures. The stamps
+// of when creatures last acted are not compared, since they are not saved
+// in snapshots.
+func sameCells(a, b *World) int {
+	for i := range a.cells {
+		ca, cb := a.cells[i], b.cells[i]
+		if ca.Kind != cb.Kind || ca.Starve != cb.Starve || ca.Breed != cb.Breed {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestPartitionGrid(t *testing.T) {
+	tests := []struct {
+		threads    int
+		cols, rows int
+	}{
+		{1, 1, 1},
+		{2, 2, 1},
+		{4, 2, 2},
+		{6, 3, 2},
+		{8, 4, 2},
+		{7, 7, 1},
+	}
+	for _, tt := range tests {
+		regions := partitionGrid(tt.threads, 100, 60)
+		if len(regions) != tt.threads {
+			t.Fatalf("%d threads: got %d regions", tt.threads, len(regions))
+		}
+		covered := 0
+		for _, r := range regions {
+			covered += (r.x1 - r.x0) * (r.y1 - r.y0)
+		}
+		if covered != 100*60 {
+			t.Errorf("%d threads: regions cover %d cells, want %d", tt.threads, covered, 100*60)
+		}
+		if last := regions[len(regions)-1]; last.x0 != (tt.cols-1)*100/tt.cols || last.y0 != (tt.rows-1)*60/tt.rows {
+			t.Errorf("%d threads: last region %+v, want a %dx%d layout", tt.threads, last, tt.cols, tt.rows)
+		}
+	}
+}
+
+func TestDeterministicIgnoresThreadCount(t *testing.T) {
+	for _, hood := range Neighbourhoods {
+		for _, boundary := range Boundaries {
+			for _, rules := range RuleSets {
+				for _, schedule := range Schedules {
+					edit := func(cfg *Config) {
+						cfg.Width, cfg.Height = 24, 24
+						cfg.NumFish, cfg.NumShark = 150, 20
+						cfg.Neighbourhood, cfg.Boundary, cfg.Rules, cfg.Schedule = hood, boundary, rules, schedule
+						cfg.Deterministic, cfg.Seed = true, 11
+					}
+					if testConfig(edit).Validate() != nil {
+						continue
+					}
+					t.Run(string(hood)+"/"+string(boundary)+"/"+string(rules)+"/"+string(schedule), func(t *testing.T) {
+						run := func(threads int) *World {
+							w := newTestWorld(t, func(cfg *Config) {
+								edit(cfg)
+								cfg.Threads = threads
+							})
+							for range 20 {
+								w.Step()
+							}
+							return w
+						}
+						want := run(1)
+						for _, threads := range []int{2, 3, 5, 8, 64} {
+							if i := sameCells(want, run(threads)); i >= 0 {
+								t.Errorf("%d threads: cell %d differs from the single-threaded run", threads, i)
+							}
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestSeedCellKeepsSeedAndChrononApart(t *testing.T) {
+	// Seed s at chronon c+1 must not draw the numbers of seed s+1 at chronon
+	// c, or runs with neighbouring seeds would replay each other's draws.
+	draw := func(seed uint64, chronon int) uint64 {
+		w := newTestWorld(t, func(cfg *Config) { cfg.Seed, cfg.Deterministic = seed, true })
+		w.chronon = chronon
+		wk := &w.workers[0]
+		w.seedCell(wk, 3, 4)
+		return wk.rng.Uint64()
+	}
+	if draw(1, 6) == draw(2, 5) {
+		t.Error("seed 1 at chronon 6 draws the same numbers as seed 2 at chronon 5")
+	}
+	if draw(1, 5) != draw(1, 5) {
+		t.Error("the same seed, chronon and cell draw different numbers")
+	}
+}
+
+func TestAccessorsPanicOutsideGrid(t *testing.T) {
+	w := newTestWorld(t, func(cfg *Config) {
+		cfg.Width, cfg.Height, cfg.NumFish, cfg.NumShark = 5, 4, 3, 1
+	})
+	for _, p := range [][2]int{{-1, 0}, {0, -1}, {5, 0}, {0, 4}, {5, 4}} {
+		for name, get := range map[string]func(x, y int){
+			"Cell":    func(x, y int) { w.Cell(x, y) },
+			"Terrain": func(x, y int) { w.Terrain(x, y) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s(%d, %d) on a 5x4 grid did not panic", name, p[0], p[1])
+					}
+				}()
+				get(p[0], p[1])
+			}()
+		}
+	}
+}
+
+// BenchmarkStep measures a full chronon for several grid sizes, update modes,
+// schedules and thread counts. Racing threads in the partitioned mode would
+// trip the race detector, so that mode is only run with one thread, where
+// the two schedules differ only in how the grid is cut up.
+func BenchmarkStep(b *testing.B) {
+	for _, size := range []int{64, 256, 512} {
+		for _, mode := range Modes {
+			for _, schedule := range Schedules {
+				for _, threads := range []int{1, 2, 4, 8} {
+					if mode == ModePartitioned && threads > 1 {
+						continue
+					}
+					b.Run(fmt.Sprintf("%dx%d/%s/%s/threads=%d", size, size, mode, schedule, threads), func(b *testing.B) {
+						w := newTestWorld(b, func(cfg *Config) {
+							cfg.Width, cfg.Height = size, size
+							cfg.NumFish, cfg.NumShark = size*size/4, size*size/40
+							cfg.Mode, cfg.Schedule, cfg.Threads = mode, schedule, threads
+						})
+						b.ResetTimer()
+						for range b.N {
+							w.Step()
+						}
+					})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package wator
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Map files.
+//
+// A map file describes every cell of the grid: its terrain and the creature,
+// if any, living in it. Maps are used to set up the starting world, or just
+// its terrain, and a world can be written back out as a map to share a
+// scenario. The format is chosen by the file extension: ".png" for an image
+// with one pixel per cell, anything else for text with one line per row and
+// one character per cell.
+//
+// Text maps use the characters of mapLegend. In images, creatures are
+// recognised by the exact colours of mapLegend; any other pixel only gives
+// terrain, with dark pixels read as land, distinctly green pixels as reef and
+// everything else as sea, so maps can be drawn in any paint program.
+//
+// Maps hold no breeding or starvation counters: creatures read from a map
+// start out like newly placed ones.
+
+// square is what a map says about a single cell.
+type square struct {
+	kind    Kind
+	terrain Terrain
+}
+
+// mapLegend lists every square a map can hold, with its character in text
+// maps and its colour in map images.
+var mapLegend = []struct {
+	square
+	char   byte
+	colour color.RGBA
+}{
+	{square{Water, Sea}, '.', color.RGBA{255, 255, 255, 255}},
+	{square{Water, Land}, '#', color.RGBA{0, 0, 0, 255}},
+	{square{Water, Reef}, '~', color.RGBA{0, 160, 0, 255}},
+	{square{Fish, Sea}, 'f', color.RGBA{255, 255, 0, 255}},
+	{square{Fish, Reef}, 'F', color.RGBA{128, 255, 0, 255}},
+	{square{Shark, Sea}, 's', color.RGBA{255, 0, 0, 255}},
+}
+
+// readMap reads a map of a width x height grid from path, returning its
+// squares indexed like the grid.
+func readMap(path string, width, height int) ([]square, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var squares []square
+	if isImage(path) {
+		squares, err = decodeMapImage(data, width, height)
+	} else {
+		squares, err = decodeMapText(data, width, height)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return squares, nil
+}
+
+// loadMap fills in the terrain of the world from the map file at path, and
+// also its creatures if creatures is set.
+func (w *World) loadMap(path string, creatures bool) error {
+	squares, err := readMap(path, w.cfg.Width, w.cfg.Height)
+	if err != nil {
+		return err
+	}
+	w.terrain = make([]Terrain, len(squares))
+	for i, sq := range squares {
+		w.terrain[i] = sq.terrain
+		if creatures && sq.kind != Water {
+			w.cells[i] = w.newborn(sq.kind, 0)
+		}
+	}
+	return nil
+}
+
+// isImage reports whether the map file at path is an image.
+func isImage(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".png")
+}
+
+// decodeMapText parses a text map.
+func decodeMapText(data []byte, width, height int) ([]square, error) {
+	rows := strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+	if len(rows) != height {
+		return nil, fmt.Errorf("map has %d rows, want %d", len(rows), height)
+	}
+	squares := make([]square, width*height)
+	for y, row := range rows {
+		if len(row) != width {
+			return nil, fmt.Errorf("row %d is %d cells wide, want %d", y+1, len(row), width)
+		}
+	cells:
+		for x := range width {
+			for _, l := range mapLegend {
+				if l.char == row[x] {
+					squares[x*height+y] = l.square
+					continue cells
+				}
+			}
+			return nil, fmt.Errorf("row %d, column %d: unknown symbol %q", y+1, x+1, row[x])
+		}
+	}
+	return squares, nil
+}
+
+// decodeMapImage parses a map image.
+func decodeMapImage(data []byte, width, height int) ([]square, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	if b.Dx() != width || b.Dy() != height {
+		return nil, fmt.Errorf("image is %dx%d, want %dx%d", b.Dx(), b.Dy(), width, height)
+	}
+	squares := make([]square, width*height)
+	for x := range width {
+		for y := range height {
+			squares[x*height+y] = squareOf(img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return squares, nil
+}
+
+// squareOf returns the square shown by a pixel of a map image.
+func squareOf(c color.Color) square {
+	r, g, b, _ := c.RGBA()
+	r, g, b = r>>8, g>>8, b>>8
+	for _, l := range mapLegend {
+		if l.kind != Water && uint32(l.colour.R) == r && uint32(l.colour.G) == g && uint32(l.colour.B) == b {
+			return l.square
+		}
+	}
+	switch {
+	case max(r, g, b) < 64:
+		return square{Water, Land}
+	case g > r+64 && g > b+64:
+		return square{Water, Reef}
+	}
+	return square{Water, Sea}
+}
+
+// SaveMap writes the world to path as a map file, as a PNG image if the path
+// ends in ".png" and as text otherwise. Breeding and starvation counters are
+// not saved.
+func (w *World) SaveMap(path string) error {
+	var buf bytes.Buffer
+	if isImage(path) {
+		img := image.NewRGBA(image.Rect(0, 0, w.cfg.Width, w.cfg.Height))
+		for x := range w.cfg.Width {
+			for y := range w.cfg.Height {
+				img.SetRGBA(x, y, mapLegend[w.legendIndex(x, y)].colour)
+			}
+		}
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+	} else {
+		for y := range w.cfg.Height {
+			for x := range w.cfg.Width {
+				buf.WriteByte(mapLegend[w.legendIndex(x, y)].char)
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// legendIndex returns the entry of mapLegend describing the cell at (x, y).
+func (w *World) legendIndex(x, y int) int {
+	sq := square{w.at(x, y).Kind, w.terrainAt(x, y)}
+	for i, l := range mapLegend {
+		if l.square == sq {
+			return i
+		}
+	}
+	panic(fmt.Sprintf("wator: %s on %s at (%d, %d)", sq.kind, sq.terrain, x, y))
+}
@@ -0,0 +1,113 @@
+package wator
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeMapText(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"valid", ".#~\nfFs\n", false},
+		{"windows line endings", ".#~\r\nfFs\r\n", false},
+		{"too few rows", ".#~\n", true},
+		{"short row", ".#~\nfF\n", true},
+		{"unknown symbol", ".#~\nfxs\n", true},
+	}
+	want := []square{
+		{Water, Sea}, {Fish, Sea}, // Column 0.
+		{Water, Land}, {Fish, Reef}, // Column 1.
+		{Water, Reef}, {Shark, Sea}, // Column 2.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			squares, err := decodeMapText([]byte(tt.data), 3, 2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			for i := range squares {
+				if squares[i] != want[i] {
+					t.Errorf("square %d = %v, want %v", i, squares[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSquareOf(t *testing.T) {
+	tests := []struct {
+		c    color.RGBA
+		want square
+	}{
+		{color.RGBA{255, 255, 0, 255}, square{Fish, Sea}},
+		{color.RGBA{128, 255, 0, 255}, square{Fish, Reef}},
+		{color.RGBA{255, 0, 0, 255}, square{Shark, Sea}},
+		{color.RGBA{20, 20, 20, 255}, square{Water, Land}},
+		{color.RGBA{30, 200, 40, 255}, square{Water, Reef}},
+		{color.RGBA{0, 90, 200, 255}, square{Water, Sea}},
+		{color.RGBA{250, 250, 10, 255}, square{Water, Sea}}, // Creatures need their exact colour.
+	}
+	for _, tt := range tests {
+		if got := squareOf(tt.c); got != tt.want {
+			t.Errorf("squareOf(%v) = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestSaveMapRoundTrip(t *testing.T) {
+	for _, name := range []string{"world.txt", "world.png"} {
+		t.Run(name, func(t *testing.T) {
+			w := terrainWorld(t, "#~..\n.~..\n....\n", 4, 3)
+			*w.at(1, 0) = Cell{Kind: Fish}
+			*w.at(3, 2) = Cell{Kind: Shark, Starve: 1}
+			*w.at(2, 1) = Cell{Kind: Fish}
+			path := filepath.Join(t.TempDir(), name)
+			if err := w.SaveMap(path); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := w.Config()
+			cfg.TerrainMap, cfg.WorldMap = "", path
+			loaded, err := New(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for x := range 4 {
+				for y := range 3 {
+					if got, want := loaded.Terrain(x, y), w.Terrain(x, y); got != want {
+						t.Errorf("terrain at (%d, %d) = %s, want %s", x, y, got, want)
+					}
+					if got, want := loaded.Cell(x, y).Kind, w.Cell(x, y).Kind; got != want {
+						t.Errorf("cell at (%d, %d) holds %s, want %s", x, y, got, want)
+					}
+				}
+			}
+			if got := loaded.Cell(3, 2).Starve; got != cfg.SharkStarve {
+				t.Errorf("loaded shark has Starve %d, want a fresh %d", got, cfg.SharkStarve)
+			}
+		})
+	}
+}
+
+func TestNewIgnoresPopulationsWithWorldMap(t *testing.T) {
+	// The default populations would never fit in a 4x3 grid, but a world map
+	// brings its own.
+	path := filepath.Join(t.TempDir(), "small.txt")
+	if err := os.WriteFile(path, []byte("f.s.\n.#..\nf..f\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height, cfg.WorldMap, cfg.Seed = 4, 3, path, 1
+	w, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Fish() != 3 || w.Sharks() != 1 {
+		t.Errorf("got %d fish and %d sharks, want the map's 3 and 1", w.Fish(), w.Sharks())
+	}
+}